@@ -0,0 +1,70 @@
+//go:build iradix_audit
+
+package iradix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"unsafe"
+)
+
+// nodeAudit records a checksum of the structure of a node when the transaction
+// that created it is committed. Building with the iradix_audit tag enables
+// these checks, which catch code that mutates nodes of a committed tree, for
+// example by writing to a key slice returned by an iterator. Any read that
+// visits a node that changed since it was committed panics.
+type nodeAudit struct {
+	sealed bool
+	sum    uint64
+}
+
+// auditChecksum computes the checksum of the parts of the node that must not
+// change once it is committed. Reference counts and watch channels are left
+// out as they are legitimately updated later.
+func (n *Node[T]) auditChecksum() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	writePtr := func(p unsafe.Pointer) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(uintptr(p)))
+		h.Write(buf[:])
+	}
+	h.Write(n.prefix)
+	writePtr(unsafe.Pointer(n.leaf))
+	if n.leaf != nil {
+		h.Write(n.leaf.key)
+	}
+	for _, e := range n.edges {
+		h.Write([]byte{e.label})
+		writePtr(unsafe.Pointer(e.node))
+	}
+	return h.Sum64()
+}
+
+// auditSeal records the checksum of every node under n that hasn't been sealed
+// yet. It is called when a transaction is committed.
+func (n *Node[T]) auditSeal() {
+	if n.audit.sealed {
+		return
+	}
+	for _, e := range n.edges {
+		e.node.auditSeal()
+	}
+	n.audit.sum = n.auditChecksum()
+	n.audit.sealed = true
+}
+
+// auditCheckWrite panics if a transaction is about to modify a committed node
+// in place, which would change a tree that may still be read.
+func (n *Node[T]) auditCheckWrite() {
+	if n.audit.sealed {
+		panic(fmt.Sprintf("iradix: committed node with prefix %q modified in place", n.prefix))
+	}
+}
+
+// auditCheck panics if the node was modified since it was sealed.
+func (n *Node[T]) auditCheck() {
+	if n.audit.sealed && n.auditChecksum() != n.audit.sum {
+		panic(fmt.Sprintf("iradix: committed node with prefix %q was modified", n.prefix))
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !iradix_audit
+
+package iradix
+
+// nodeAudit is empty unless built with the iradix_audit tag, see audit.go.
+type nodeAudit struct{}
+
+func (n *Node[T]) auditSeal()       {}
+func (n *Node[T]) auditCheckWrite() {}
+func (n *Node[T]) auditCheck()      {}
@@ -0,0 +1,30 @@
+//go:build iradix_audit
+
+package iradix
+
+import "testing"
+
+func TestAudit_DetectsMutation(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"foo", "foobar", "foo/bar", "zip"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	// Normal use of the tree doesn't trip the checks.
+	txn := r.Txn(false)
+	txn.Insert([]byte("zap"), 5)
+	txn.Delete([]byte("foo"))
+	r2 := txn.Commit()
+	r.Root().Walk(func(k []byte, v int) bool { return false })
+	r2.Root().Walk(func(k []byte, v int) bool { return false })
+
+	// Writing to a committed node's prefix does.
+	_, child := r.Root().getEdge('f')
+	child.prefix[0] = 'g'
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	r.Get([]byte("foo"))
+}
@@ -0,0 +1,107 @@
+package iradix
+
+// settle brings the state derived from the subtree below every unsettled node
+// under n up to date, according to the tree's configuration. This is done when
+// a transaction is committed. Since the path from the root to every modified
+// node is copied, a settled node can only have settled children, so only the
+// paths modified by the transaction are visited. Every settled node is also
+// stamped with a new generation.
+func (n *Node[T]) settle(c *config[T]) {
+	if n.settled {
+		return
+	}
+	for _, e := range n.edges {
+		e.node.settle(c)
+	}
+	n.generation = nextGeneration()
+	n.updateLeaves()
+	n.updateLabels()
+	n.updateWatchAllocation(c.watchAllocationPolicy())
+	if !c.settles() {
+		n.settled = true
+		return
+	}
+	// The aggregates are replaced rather than modified, since clones of
+	// the node share them.
+	n.agg = &nodeAggregates{}
+	if c.leafHash != nil {
+		n.updateHash(c.leafHash)
+	}
+	if c.leafWeight != nil {
+		n.updateWeight(c.leafWeight)
+	}
+	if c.sizer != nil {
+		n.updateSize(c.sizer)
+	}
+	n.settled = true
+}
+
+// unsettle marks a node that is being modified in place, dropping the state
+// derived from its old contents.
+func (n *Node[T]) unsettle() {
+	n.settled = false
+	n.agg = nil
+	n.labels = nil
+}
+
+// nodeAggregates is the state derived from the subtree below a node that is
+// only needed by some of the tree's options. Keeping it apart spares the nodes
+// of trees that don't use them the memory.
+type nodeAggregates struct {
+	// hash is the content hash of the subtree, see WithLeafHash.
+	hash []byte
+
+	// weight is the sum of the leaf weights in the subtree, see
+	// WithLeafWeight.
+	weight uint64
+
+	// size is the sum of the sizes of the values in the subtree, see
+	// WithSizer.
+	size int64
+}
+
+// leafExtra is the state of a leaf that is only needed by some of the tree's
+// options, kept apart like nodeAggregates.
+type leafExtra struct {
+	// hash is the leaf hash computed by the tree's LeafHashFn, if any.
+	hash []byte
+
+	// seq is the position of the leaf in the insertion order index, if the
+	// tree has one, see WithInsertionOrder.
+	seq uint64
+}
+
+// clone returns a copy of the extra state, which may be nil.
+func (e *leafExtra) clone() *leafExtra {
+	if e == nil {
+		return nil
+	}
+	ne := *e
+	return &ne
+}
+
+// getHash returns the leaf hash, or nil if it wasn't computed.
+func (n *leafNode[T]) getHash() []byte {
+	if n.extra == nil {
+		return nil
+	}
+	return n.extra.hash
+}
+
+// getSeq returns the position of the leaf in the insertion order index.
+func (n *leafNode[T]) getSeq() uint64 {
+	if n.extra == nil {
+		return 0
+	}
+	return n.extra.seq
+}
+
+// newLeaf returns a new leaf for the transaction, only allocating the extra
+// state if the tree has an insertion order index.
+func (t *Txn[T]) newLeaf(k []byte, v T) *leafNode[T] {
+	l := &leafNode[T]{key: k, val: v, refCount: 1}
+	if t.order != nil {
+		l.extra = &leafExtra{seq: t.orderSeq}
+	}
+	return l
+}
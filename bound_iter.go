@@ -0,0 +1,89 @@
+package iradix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrRootChanged is returned when a BoundIterator is resumed against a root
+// other than the one it was created from.
+var ErrRootChanged = errors.New("iterator resumed against a different root")
+
+// RootMismatchPolicy selects what a BoundIterator does when it is resumed
+// against a different root.
+type RootMismatchPolicy int
+
+const (
+	// RootMismatchError makes Resume return ErrRootChanged.
+	RootMismatchError RootMismatchPolicy = iota
+
+	// RootMismatchPanic makes Resume panic, for catching the mistake in
+	// tests and debug builds.
+	RootMismatchPanic
+)
+
+// BoundIterator is an iterator that remembers the root it was created from and
+// its position, so that a scan split into several pages can be resumed later.
+// Resuming it against any other root is reported rather than silently mixing
+// entries from different versions of the tree in one scan.
+type BoundIterator[T any] struct {
+	root   *Node[T]
+	policy RootMismatchPolicy
+	iter   *Iterator[T]
+
+	// last is the last key returned, if hasLast is set. The empty key
+	// is a valid last key, so a nil last doesn't tell.
+	last    []byte
+	hasLast bool
+}
+
+// BoundIterator returns an iterator over all the keys under the node that is
+// bound to it, see BoundIterator.
+func (n *Node[T]) BoundIterator(policy RootMismatchPolicy) *BoundIterator[T] {
+	return &BoundIterator[T]{
+		root:   n,
+		policy: policy,
+		iter:   n.Iterator(),
+	}
+}
+
+// Root returns the root the iterator is bound to.
+func (b *BoundIterator[T]) Root() *Node[T] {
+	return b.root
+}
+
+// Next returns the next key in order.
+func (b *BoundIterator[T]) Next() ([]byte, T, bool) {
+	k, v, ok := b.iter.Next()
+	if ok && b.hasLast && bytes.Equal(k, b.last) {
+		// Skip the key the iterator was resumed after
+		k, v, ok = b.iter.Next()
+	}
+	if ok {
+		b.last, b.hasLast = k, true
+	}
+	return k, v, ok
+}
+
+// Resume checks that the given root holds the same version of the tree as the
+// one the iterator is bound to, before carrying on from where it left off. The
+// root may be a different node holding the same version, such as the root of
+// a clone of the tree, see Node.Generation. The iteration is resumed on the
+// given root, just after the last key returned, as BudgetIterator.SeekAfter
+// does. If the version differs, it either returns ErrRootChanged or panics
+// depending on the policy, and the iterator is left as it was.
+func (b *BoundIterator[T]) Resume(root *Node[T]) error {
+	if root != b.root && (root == nil || root.generation == 0 || root.generation != b.root.generation) {
+		if b.policy == RootMismatchPanic {
+			panic(fmt.Sprintf("iradix: iterator resumed against a different root after key %q", b.last))
+		}
+		return ErrRootChanged
+	}
+	b.root = root
+	b.iter = root.Iterator()
+	if b.hasLast {
+		b.iter.SeekLowerBound(b.last)
+	}
+	return nil
+}
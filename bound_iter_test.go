@@ -0,0 +1,70 @@
+package iradix
+
+import "testing"
+
+func TestBoundIterator(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a", "b", "c", "d"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	it := r.Root().BoundIterator(RootMismatchError)
+	if k, _, _ := it.Next(); string(k) != "a" {
+		t.Fatalf("bad key: %q", k)
+	}
+
+	// Resuming against the same root carries on.
+	if err := it.Resume(r.Root()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if k, _, _ := it.Next(); string(k) != "b" {
+		t.Fatalf("bad key: %q", k)
+	}
+
+	// A different version of the tree is rejected.
+	r2, _, _ := r.Insert([]byte("bb"), 9)
+	if err := it.Resume(r2.Root()); err != ErrRootChanged {
+		t.Fatalf("expected error, got %v", err)
+	}
+	if k, _, _ := it.Next(); string(k) != "c" {
+		t.Fatalf("bad key: %q", k)
+	}
+
+	// A root holding the same version resumes after the last key.
+	clone := r.Clone()
+	if err := it.Resume(clone.Root()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if it.Root() != clone.Root() {
+		t.Fatalf("should be bound to the new root")
+	}
+	if k, _, _ := it.Next(); string(k) != "d" {
+		t.Fatalf("bad key: %q", k)
+	}
+	if _, _, ok := it.Next(); ok {
+		t.Fatalf("should be done")
+	}
+
+	// The empty key is a last key like any other.
+	e, _, _ := New[int]().Insert(nil, 0)
+	e, _, _ = e.Insert([]byte("a"), 1)
+	it = e.Root().BoundIterator(RootMismatchError)
+	if k, _, _ := it.Next(); len(k) != 0 {
+		t.Fatalf("bad key: %q", k)
+	}
+	if err := it.Resume(e.Root()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if k, _, _ := it.Next(); string(k) != "a" {
+		t.Fatalf("bad key: %q", k)
+	}
+
+	// Or panics if configured to.
+	it = r.Root().BoundIterator(RootMismatchPanic)
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	it.Resume(r2.Root())
+}
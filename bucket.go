@@ -0,0 +1,61 @@
+package iradix
+
+import "errors"
+
+// ErrEmptyBucketKey is returned by ExportBucket for a tree holding the empty
+// key, which bolt doesn't allow.
+var ErrEmptyBucketKey = errors.New("empty key can't be exported to a bucket")
+
+// BucketWriter is the part of a bbolt bucket (*bbolt.Bucket) used by
+// ExportBucket. Keeping it to an interface means this package doesn't depend on
+// bbolt, and other key/value stores can be used too.
+type BucketWriter interface {
+	Put(key, value []byte) error
+}
+
+// BucketReader is the part of a bbolt bucket (*bbolt.Bucket) used by
+// ImportBucket.
+type BucketReader interface {
+	ForEach(fn func(k, v []byte) error) error
+}
+
+// ExportBucket writes every key under the node into the bucket, encoding the
+// values with encode. This is meant for inspecting a tree with existing bolt
+// tooling, and must be called within a writable bolt transaction. Bolt
+// doesn't allow empty keys, so if the empty key is under the node it returns
+// ErrEmptyBucketKey before writing anything.
+func ExportBucket[T any](n *Node[T], b BucketWriter, encode func(T) ([]byte, error)) error {
+	if n.leaf != nil && len(n.leaf.key) == 0 {
+		return ErrEmptyBucketKey
+	}
+	return n.WalkErr(func(k []byte, v T) error {
+		enc, err := encode(v)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, enc)
+	})
+}
+
+// ImportBucket builds a tree, configured with the given options, from every
+// key in the bucket, decoding the values with decode. Nested buckets are
+// skipped. The keys and values handed out by bolt are only valid for the life
+// of its transaction, so they are copied before being stored or decoded.
+func ImportBucket[T any](b BucketReader, decode func([]byte) (T, error), opts ...Option[T]) (*Tree[T], error) {
+	txn := New[T](opts...).Txn(false)
+	err := b.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+		val, err := decode(concat(nil, v))
+		if err != nil {
+			return err
+		}
+		txn.Insert(concat(nil, k), val)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txn.Commit(), nil
+}
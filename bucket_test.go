@@ -0,0 +1,77 @@
+package iradix
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// mapBucket is a stand in for a bbolt bucket.
+type mapBucket map[string][]byte
+
+func (b mapBucket) Put(k, v []byte) error {
+	if len(k) == 0 {
+		return errors.New("key required")
+	}
+	b[string(k)] = v
+	return nil
+}
+
+func (b mapBucket) ForEach(fn func(k, v []byte) error) error {
+	keys := make([]string, 0, len(b))
+	for k := range b {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn([]byte(k), b[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestBucket_ExportImport(t *testing.T) {
+	r := New[int]()
+	keys := []string{"foo", "foo/bar", "foobar", "zip"}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	b := make(mapBucket)
+	encode := func(v int) ([]byte, error) { return []byte(strconv.Itoa(v)), nil }
+	if err := ExportBucket(r.Root(), b, encode); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(b) != len(keys) || string(b["foobar"]) != "2" {
+		t.Fatalf("bad bucket: %v", b)
+	}
+
+	// Nested buckets show up with nil values and are skipped.
+	b["nested"] = nil
+	r2, err := ImportBucket(b, func(v []byte) (int, error) { return strconv.Atoi(string(v)) })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	verifyTree(t, keys, r2)
+	if v, _ := r2.Get([]byte("zip")); v != 3 {
+		t.Fatalf("bad value: %d", v)
+	}
+
+	// Errors are passed through.
+	b["bad"] = []byte("x")
+	if _, err := ImportBucket(b, func(v []byte) (int, error) { return strconv.Atoi(string(v)) }); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	// The empty key is refused before anything is written.
+	r, _, _ = r.Insert(nil, 42)
+	b = make(mapBucket)
+	if err := ExportBucket(r.Root(), b, encode); !errors.Is(err, ErrEmptyBucketKey) {
+		t.Fatalf("expected ErrEmptyBucketKey, got %v", err)
+	}
+	if len(b) != 0 {
+		t.Fatalf("nothing should be written: %v", b)
+	}
+}
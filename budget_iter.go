@@ -0,0 +1,108 @@
+package iradix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by BudgetIterator.Err when the iterator
+// stopped because its time budget ran out.
+var ErrBudgetExceeded = errors.New("iteration time budget exceeded")
+
+// Budget limits the time a BudgetIterator may spend.
+type Budget struct {
+	// Context stops the iteration once it is done, if set.
+	Context context.Context
+
+	// Time is the time the iteration may take, measured from the creation
+	// of the iterator, or zero for no limit.
+	Time time.Duration
+
+	// CheckEvery is the number of elements returned between checks of the
+	// budget. Zero checks on every element.
+	CheckEvery int
+}
+
+// BudgetIterator is an iterator that cooperatively stops once its budget is
+// spent, so that a long scan doesn't monopolize a latency sensitive caller.
+// When it stops early, Err reports why and Cursor returns the point to resume
+// from with a new iterator and SeekAfter.
+type BudgetIterator[T any] struct {
+	iter     *Iterator[T]
+	budget   Budget
+	deadline time.Time
+	count    int
+	last     []byte
+	err      error
+}
+
+// BudgetIterator returns an iterator over all the keys under the node that
+// stops once the given budget is spent.
+func (n *Node[T]) BudgetIterator(budget Budget) *BudgetIterator[T] {
+	b := &BudgetIterator[T]{
+		iter:   n.Iterator(),
+		budget: budget,
+	}
+	if budget.Time > 0 {
+		b.deadline = time.Now().Add(budget.Time)
+	}
+	return b
+}
+
+// SeekAfter positions the iterator just after the given key, which is
+// normally the Cursor of a previous iterator that stopped early.
+func (b *BudgetIterator[T]) SeekAfter(cursor []byte) {
+	b.iter.SeekLowerBound(cursor)
+	b.last = cursor
+}
+
+// Next returns the next key in order. It returns false once the iteration is
+// done, or once the budget is spent, in which case Err is set.
+func (b *BudgetIterator[T]) Next() ([]byte, T, bool) {
+	var zero T
+	if b.err != nil {
+		return nil, zero, false
+	}
+	if b.count%max(b.budget.CheckEvery, 1) == 0 {
+		if b.err = b.check(); b.err != nil {
+			return nil, zero, false
+		}
+	}
+	k, v, ok := b.iter.Next()
+	if ok && b.last != nil && bytes.Equal(k, b.last) {
+		// Skip the key the iterator was positioned after
+		k, v, ok = b.iter.Next()
+	}
+	if ok {
+		b.last = k
+		b.count++
+	}
+	return k, v, ok
+}
+
+// check returns the reason to stop the iteration, if any.
+func (b *BudgetIterator[T]) check() error {
+	if b.budget.Context != nil {
+		if err := b.budget.Context.Err(); err != nil {
+			return err
+		}
+	}
+	if !b.deadline.IsZero() && time.Now().After(b.deadline) {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// Err returns ErrBudgetExceeded or the context's error if the iterator stopped
+// early, and nil otherwise.
+func (b *BudgetIterator[T]) Err() error {
+	return b.err
+}
+
+// Cursor returns the last key returned, from which the iteration can be
+// resumed with SeekAfter.
+func (b *BudgetIterator[T]) Cursor() []byte {
+	return b.last
+}
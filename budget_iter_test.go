@@ -0,0 +1,58 @@
+package iradix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBudgetIterator(t *testing.T) {
+	r := New[int]()
+	txn := r.Txn(false)
+	for i := 0; i < 100; i++ {
+		txn.Insert([]byte(fmt.Sprintf("%03d", i)), i)
+	}
+	r = txn.Commit()
+
+	// A cancelled context stops the scan at the next check, and the scan
+	// resumes from the cursor without gaps or duplicates.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := r.Root().BudgetIterator(Budget{Context: ctx, CheckEvery: 10})
+	var seen []int
+	for _, v, ok := it.Next(); ok; _, v, ok = it.Next() {
+		seen = append(seen, v)
+		if v == 24 {
+			cancel()
+		}
+	}
+	if !errors.Is(it.Err(), context.Canceled) || len(seen) != 30 {
+		t.Fatalf("bad: %v %d", it.Err(), len(seen))
+	}
+	if string(it.Cursor()) != "029" {
+		t.Fatalf("bad cursor: %q", it.Cursor())
+	}
+
+	it2 := r.Root().BudgetIterator(Budget{})
+	it2.SeekAfter(it.Cursor())
+	for _, v, ok := it2.Next(); ok; _, v, ok = it2.Next() {
+		seen = append(seen, v)
+	}
+	if it2.Err() != nil || len(seen) != 100 {
+		t.Fatalf("bad: %v %d", it2.Err(), len(seen))
+	}
+	for i, v := range seen {
+		if v != i {
+			t.Fatalf("bad order at %d: %d", i, v)
+		}
+	}
+
+	// An exhausted time budget stops the scan.
+	it = r.Root().BudgetIterator(Budget{Time: time.Nanosecond})
+	time.Sleep(time.Millisecond)
+	if _, _, ok := it.Next(); ok || !errors.Is(it.Err(), ErrBudgetExceeded) {
+		t.Fatalf("bad: %v", it.Err())
+	}
+}
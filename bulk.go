@@ -0,0 +1,168 @@
+package iradix
+
+import (
+	"bytes"
+	"context"
+)
+
+// ctxCheckInterval is the number of keys a bulk operation processes between
+// checks of the transaction's context.
+const ctxCheckInterval = 1024
+
+// TxnCtx starts a new transaction like Txn, whose bulk operations (BulkInsert,
+// DeleteRange and UpdatePrefix) periodically check the given context and stop
+// with its error once it is done. The writes made before that remain in the
+// transaction, so a cancelled transaction should normally be discarded rather
+// than committed.
+func (t *Tree[T]) TxnCtx(ctx context.Context, clone bool) *Txn[T] {
+	txn := t.Txn(clone)
+	txn.ctx = ctx
+	return txn
+}
+
+// checkCtx returns the error of the transaction's context if it is done. It
+// only checks every ctxCheckInterval calls, counted by i.
+func (t *Txn[T]) checkCtx(i int) error {
+	if t.ctx == nil || i%ctxCheckInterval != 0 {
+		return nil
+	}
+	return t.ctx.Err()
+}
+
+// BulkProgress reports how far a bulk insert got, see SetBulkProgress.
+type BulkProgress struct {
+	// Keys is the number of keys inserted so far and Bytes their total
+	// length.
+	Keys  int
+	Bytes int64
+
+	// Nodes is the number of nodes the inserts added to the tree, and
+	// MaxDepth the depth in edges of the deepest node they created or
+	// updated.
+	Nodes    int
+	MaxDepth int
+}
+
+// bulkProgress holds the progress callback of a transaction and the
+// statistics collected for it.
+type bulkProgress struct {
+	every int
+	fn    func(BulkProgress)
+	stats BulkProgress
+}
+
+// created records nodes added by an insert, the deepest at the given depth.
+// It does nothing on a nil bulkProgress, so that inserts don't need to check
+// whether progress is being reported.
+func (b *bulkProgress) created(nodes, depth int) {
+	if b == nil {
+		return
+	}
+	b.stats.Nodes += nodes
+	b.stats.MaxDepth = max(b.stats.MaxDepth, depth)
+}
+
+// SetBulkProgress makes BulkInsert call fn every given number of keys, and
+// once more when it returns, so that long running loads can display their
+// progress or be health checked. The statistics add up over all the bulk
+// inserts of the transaction. A nil fn turns reporting off.
+func (t *Txn[T]) SetBulkProgress(every int, fn func(BulkProgress)) {
+	if fn == nil {
+		t.progress = nil
+		return
+	}
+	t.progress = &bulkProgress{every: max(every, 1), fn: fn}
+}
+
+// BulkInsert inserts all the pairs yielded by seq, returning the number
+// inserted. It stops early with the context's error if the transaction was
+// started with TxnCtx and the context is done, or with an *InvalidKeyError
+// at the first key rejected by the tree's key validator.
+func (t *Txn[T]) BulkInsert(seq func(yield func(k []byte, v T) bool)) (int, error) {
+	var n int
+	var err error
+	p := t.progress
+	seq(func(k []byte, v T) bool {
+		if err = t.checkCtx(n); err != nil {
+			return false
+		}
+		if _, _, err = t.TryInsert(k, v); err != nil {
+			return false
+		}
+		n++
+		if p != nil {
+			p.stats.Keys++
+			p.stats.Bytes += int64(len(k))
+			if p.stats.Keys%p.every == 0 {
+				p.fn(p.stats)
+			}
+		}
+		return true
+	})
+	if p != nil {
+		p.fn(p.stats)
+	}
+	return n, err
+}
+
+// DeleteRange deletes all the keys k with from <= k < to, returning the number
+// deleted. A nil to deletes everything from from onwards. It stops early with
+// the context's error if the transaction was started with TxnCtx and the
+// context is done, or with ErrMaxTxnMutations once the transaction is full.
+func (t *Txn[T]) DeleteRange(from, to []byte) (int, error) {
+	keys, err := t.collectKeys(from, func(k []byte) bool {
+		return to == nil || bytes.Compare(k, to) < 0
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, k := range keys {
+		if err := t.checkCtx(i); err != nil {
+			return i, err
+		}
+		if _, _, err := t.TryDelete(k); err != nil {
+			return i, err
+		}
+	}
+	return len(keys), nil
+}
+
+// UpdatePrefix replaces the value of every key under the prefix with the
+// result of fn, returning the number of keys updated. It stops early with the
+// context's error if the transaction was started with TxnCtx and the context
+// is done, or with ErrMaxTxnMutations once the transaction is full.
+func (t *Txn[T]) UpdatePrefix(prefix []byte, fn func(k []byte, v T) T) (int, error) {
+	keys, err := t.collectKeys(prefix, func(k []byte) bool {
+		return bytes.HasPrefix(k, prefix)
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, k := range keys {
+		if err := t.checkCtx(i); err != nil {
+			return i, err
+		}
+		v, _ := t.Get(k)
+		if _, _, err := t.TryInsert(k, fn(k, v)); err != nil {
+			return i, err
+		}
+	}
+	return len(keys), nil
+}
+
+// collectKeys returns the keys from the lower bound onwards for which in
+// returns true, stopping at the first for which it returns false. The keys are
+// collected before any mutation since the iterator can't run over nodes that
+// the transaction modifies in place.
+func (t *Txn[T]) collectKeys(lower []byte, in func(k []byte) bool) ([][]byte, error) {
+	it := t.Root().Iterator()
+	it.SeekLowerBound(lower)
+	var keys [][]byte
+	for k, _, ok := it.Next(); ok && in(k); k, _, ok = it.Next() {
+		if err := t.checkCtx(len(keys)); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
@@ -0,0 +1,114 @@
+package iradix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func seqKeys(n int) func(yield func([]byte, int) bool) {
+	return func(yield func([]byte, int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield([]byte(fmt.Sprintf("key%05d", i)), i) {
+				return
+			}
+		}
+	}
+}
+
+func TestBulkOps(t *testing.T) {
+	txn := New[int]().TxnCtx(context.Background(), false)
+	n, err := txn.BulkInsert(seqKeys(3000))
+	if err != nil || n != 3000 || txn.Len() != 3000 {
+		t.Fatalf("bad: %d %v %d", n, err, txn.Len())
+	}
+
+	n, err = txn.UpdatePrefix([]byte("key001"), func(k []byte, v int) int { return -v })
+	if err != nil || n != 100 {
+		t.Fatalf("bad: %d %v", n, err)
+	}
+	if v, _ := txn.Get([]byte("key00150")); v != -150 {
+		t.Fatalf("bad: %d", v)
+	}
+	if v, _ := txn.Get([]byte("key00200")); v != 200 {
+		t.Fatalf("bad: %d", v)
+	}
+
+	n, err = txn.DeleteRange([]byte("key00100"), []byte("key00200"))
+	if err != nil || n != 100 || txn.Len() != 2900 {
+		t.Fatalf("bad: %d %v %d", n, err, txn.Len())
+	}
+	if _, ok := txn.Get([]byte("key00150")); ok {
+		t.Fatalf("should be deleted")
+	}
+	if _, ok := txn.Get([]byte("key00200")); !ok {
+		t.Fatalf("end of range should be kept")
+	}
+
+	n, err = txn.DeleteRange([]byte("key02000"), nil)
+	if err != nil || n != 1000 || txn.Len() != 1900 {
+		t.Fatalf("bad: %d %v %d", n, err, txn.Len())
+	}
+}
+
+func TestBulkOps_Cancel(t *testing.T) {
+	txn := New[int]().Txn(false)
+	txn.BulkInsert(seqKeys(3000))
+	r := txn.Commit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	txn = r.TxnCtx(ctx, false)
+
+	if n, err := txn.BulkInsert(seqKeys(10)); !errors.Is(err, context.Canceled) || n != 0 {
+		t.Fatalf("bad: %d %v", n, err)
+	}
+	if _, err := txn.DeleteRange(nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("bad: %v", err)
+	}
+	if _, err := txn.UpdatePrefix(nil, func(k []byte, v int) int { return v }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("bad: %v", err)
+	}
+	if txn.Len() != 3000 {
+		t.Fatalf("bad len: %d", txn.Len())
+	}
+}
+
+func TestBulkOps_Progress(t *testing.T) {
+	txn := New[int]().Txn(false)
+	var events []BulkProgress
+	txn.SetBulkProgress(100, func(p BulkProgress) {
+		events = append(events, p)
+	})
+	if _, err := txn.BulkInsert(seqKeys(250)); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(events) != 3 || events[0].Keys != 100 || events[1].Keys != 200 {
+		t.Fatalf("bad events: %v", events)
+	}
+
+	// The counts match the shape of the tree built from scratch.
+	var nodes, depth int
+	var visit func(n *Node[int], d int)
+	visit = func(n *Node[int], d int) {
+		nodes++
+		depth = max(depth, d)
+		for _, e := range n.edges {
+			visit(e.node, d+1)
+		}
+	}
+	visit(txn.Root(), 0)
+	last := events[2]
+	if last.Keys != 250 || last.Bytes != 250*8 || last.Nodes != nodes-1 || last.MaxDepth != depth {
+		t.Fatalf("bad progress: %+v, want %d nodes and depth %d", last, nodes-1, depth)
+	}
+
+	txn.SetBulkProgress(0, nil)
+	if _, err := txn.BulkInsert(seqKeys(300)); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("reporting should be off: %v", events)
+	}
+}
@@ -0,0 +1,64 @@
+package iradix
+
+import "io"
+
+// BytesView is a read-only view of a []byte value stored in a tree, see
+// GetView. It refers to the stored bytes rather than a copy of them, and keeps
+// the root of the tree it was read from alive, so it stays valid, and its
+// contents unchanged, for as long as the view is held, regardless of later
+// commits. Like every value handed out by a tree, the bytes must not be
+// modified; the view only gives read access to make that explicit.
+type BytesView struct {
+	root *Node[[]byte]
+	b    []byte
+}
+
+// GetView is used to lookup a specific key of a tree of []byte values,
+// returning a view of the value rather than a defensive copy of it, for
+// proxy-style workloads that just forward the bytes.
+func GetView(t *Tree[[]byte], k []byte) (BytesView, bool) {
+	v, ok := t.Get(k)
+	if !ok {
+		return BytesView{}, false
+	}
+	return BytesView{root: t.root, b: v}, true
+}
+
+// Len returns the number of bytes in the view.
+func (v BytesView) Len() int {
+	return len(v.b)
+}
+
+// At returns the byte at index i.
+func (v BytesView) At(i int) byte {
+	return v.b[i]
+}
+
+// Root returns the root of the tree the view was read from, which the view
+// keeps alive.
+func (v BytesView) Root() *Node[[]byte] {
+	return v.root
+}
+
+// Bytes returns the viewed bytes without copying them. They must not be
+// modified.
+func (v BytesView) Bytes() []byte {
+	return v.b
+}
+
+// AppendTo appends the viewed bytes to dst and returns the result.
+func (v BytesView) AppendTo(dst []byte) []byte {
+	return append(dst, v.b...)
+}
+
+// String returns the viewed bytes as a string, which is a copy.
+func (v BytesView) String() string {
+	return string(v.b)
+}
+
+// WriteTo implements io.WriterTo, writing the viewed bytes to w without an
+// intermediate copy.
+func (v BytesView) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(v.b)
+	return int64(n), err
+}
@@ -0,0 +1,37 @@
+package iradix
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGetView(t *testing.T) {
+	r := New[[]byte]()
+	payload := []byte("hello world")
+	r, _, _ = r.Insert([]byte("msg"), payload)
+
+	v, ok := GetView(r, []byte("msg"))
+	if !ok || v.Len() != len(payload) || v.At(0) != 'h' || v.String() != "hello world" {
+		t.Fatalf("bad view: %v %v", v, ok)
+	}
+	if &v.Bytes()[0] != &payload[0] {
+		t.Fatalf("view should not copy")
+	}
+	if v.Root() != r.Root() {
+		t.Fatalf("view should hold the root")
+	}
+
+	// Later commits don't affect the view.
+	r, _, _ = r.Insert([]byte("msg"), []byte("bye"))
+	var buf bytes.Buffer
+	if n, err := v.WriteTo(&buf); err != nil || n != int64(len(payload)) || buf.String() != "hello world" {
+		t.Fatalf("bad write: %d %v %q", n, err, buf.String())
+	}
+	if got := v.AppendTo([]byte("> ")); string(got) != "> hello world" {
+		t.Fatalf("bad append: %q", got)
+	}
+
+	if _, ok := GetView(r, []byte("missing")); ok {
+		t.Fatalf("should be missing")
+	}
+}
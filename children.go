@@ -0,0 +1,89 @@
+package iradix
+
+import "bytes"
+
+// ChildIterator is used to iterate over the distinct next path segments under
+// a prefix, see Node.Children.
+type ChildIterator[T any] struct {
+	sep   byte
+	stack []childFrame[T]
+}
+
+// childFrame is a node waiting to be visited by a ChildIterator, along with
+// its path relative to the prefix being listed.
+type childFrame[T any] struct {
+	node *Node[T]
+	path []byte
+}
+
+// Children returns an iterator over the distinct next path segments under the
+// given prefix, where segments are delimited by sep, much like listing a
+// directory. A key directly under the prefix is returned as its remaining
+// bytes, and any deeper keys are collapsed into a single segment that ends
+// with the separator. For example, listing "a/" with a '/' separator in a tree
+// holding "a/b", "a/b/c", "a/b/d" and "a/e/f" returns "b", "b/" and "e/".
+//
+// Segments are returned in key order. The iterator descends the tree structure
+// and stops at the first separator, so it doesn't visit the leaves under the
+// collapsed segments.
+func (n *Node[T]) Children(prefix []byte, sep byte) *ChildIterator[T] {
+	i := &ChildIterator[T]{sep: sep}
+	search := prefix
+	for {
+		// Check for key exhaustion
+		if len(search) == 0 {
+			i.stack = append(i.stack, childFrame[T]{node: n})
+			return i
+		}
+
+		// Look for an edge
+		_, n = n.getEdge(search[0])
+		if n == nil {
+			return i
+		}
+
+		// Consume the search prefix
+		if bytes.HasPrefix(search, n.prefix) {
+			search = search[len(n.prefix):]
+		} else if bytes.HasPrefix(n.prefix, search) {
+			// The child continues past the prefix, so part of its
+			// prefix belongs to the next segment.
+			i.stack = append(i.stack, childFrame[T]{
+				node: n,
+				path: concat(nil, n.prefix[len(search):]),
+			})
+			return i
+		} else {
+			return i
+		}
+	}
+}
+
+// Next returns the next segment, or false when there are none left.
+func (i *ChildIterator[T]) Next() ([]byte, bool) {
+	for len(i.stack) > 0 {
+		// Pop the next frame
+		f := i.stack[len(i.stack)-1]
+		i.stack = i.stack[:len(i.stack)-1]
+
+		// Everything under a separator collapses into one segment.
+		if idx := bytes.IndexByte(f.path, i.sep); idx >= 0 {
+			return f.path[:idx+1], true
+		}
+
+		// Push the children so that the smallest is visited first.
+		for j := len(f.node.edges) - 1; j >= 0; j-- {
+			child := f.node.edges[j].node
+			i.stack = append(i.stack, childFrame[T]{
+				node: child,
+				path: concat(f.path, child.prefix),
+			})
+		}
+
+		// The prefix itself isn't one of its children.
+		if f.node.leaf != nil && len(f.path) > 0 {
+			return f.path, true
+		}
+	}
+	return nil, false
+}
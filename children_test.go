@@ -0,0 +1,50 @@
+package iradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChildren(t *testing.T) {
+	r := New[int]()
+	keys := []string{
+		"a",
+		"a/b",
+		"a/b/c",
+		"a/b/d",
+		"a/bb/c",
+		"a/e/f",
+		"a/e/g/h",
+		"a/z",
+		"b/c",
+		"top",
+	}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	cases := []struct {
+		prefix string
+		expect []string
+	}{
+		{"", []string{"a", "a/", "b/", "top"}},
+		{"a/", []string{"b", "b/", "bb/", "e/", "z"}},
+		{"a/b", []string{"/", "b/"}},
+		{"a/b/", []string{"c", "d"}},
+		{"a/e/", []string{"f", "g/"}},
+		{"a/e/g/", []string{"h"}},
+		{"a/x", nil},
+		{"c", nil},
+		{"t", []string{"op"}},
+	}
+	for _, tc := range cases {
+		var out []string
+		it := r.Root().Children([]byte(tc.prefix), '/')
+		for seg, ok := it.Next(); ok; seg, ok = it.Next() {
+			out = append(out, string(seg))
+		}
+		if !reflect.DeepEqual(out, tc.expect) {
+			t.Fatalf("prefix %q: got %q, want %q", tc.prefix, out, tc.expect)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package iradix
+
+// Changed returns true if a mutation of the transaction took effect. Inserts
+// count even if they store the value already there, while deletes of missing
+// keys and rejected mutations don't.
+func (t *Txn[T]) Changed() bool {
+	return t.changed
+}
+
+// CommitChanged is like Commit, but when the transaction didn't change
+// anything, see Changed, it returns the tree it was started from, pointer
+// identical, and false, without committing or notifying anyone. Callers can
+// then skip their invalidation work with a cheap check.
+func (t *Txn[T]) CommitChanged() (*Tree[T], bool) {
+	if !t.changed {
+		return t.orig, false
+	}
+	return t.Commit(), true
+}
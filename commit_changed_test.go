@@ -0,0 +1,47 @@
+package iradix
+
+import "testing"
+
+func TestCommitChanged(t *testing.T) {
+	r := New[int](WithMaxTxnMutations[int](3))
+	r, _, _ = r.Insert([]byte("a"), 1)
+	r, _, _ = r.Insert([]byte("b"), 2)
+
+	txn := r.Txn(false)
+	txn.Delete([]byte("missing"))
+	txn.DeletePrefix([]byte("zzz"))
+	txn.SetMeta([]byte("missing"), "x")
+	if txn.Changed() {
+		t.Fatalf("nothing should have changed")
+	}
+	if nt, changed := txn.CommitChanged(); changed || nt != r {
+		t.Fatalf("should return the original tree: %v", changed)
+	}
+
+	// Rejected mutations don't count.
+	txn = r.Txn(false)
+	txn.Delete([]byte("x"))
+	txn.Delete([]byte("y"))
+	txn.Delete([]byte("z"))
+	txn.Insert([]byte("c"), 3)
+	if txn.Changed() {
+		t.Fatalf("rejected insert should not count")
+	}
+
+	for name, fn := range map[string]func(*Txn[int]){
+		"insert":       func(txn *Txn[int]) { txn.Insert([]byte("a"), 1) },
+		"delete":       func(txn *Txn[int]) { txn.Delete([]byte("a")) },
+		"deletePrefix": func(txn *Txn[int]) { txn.DeletePrefix([]byte("b")) },
+		"meta":         func(txn *Txn[int]) { txn.SetMeta([]byte("a"), "x") },
+	} {
+		txn := r.Txn(false)
+		fn(txn)
+		if !txn.Clone().Changed() {
+			t.Fatalf("%s: clone should be changed", name)
+		}
+		nt, changed := txn.CommitChanged()
+		if !changed || nt == r {
+			t.Fatalf("%s: should commit a new tree", name)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package iradix
+
+import (
+	"bytes"
+	"sort"
+)
+
+// CompactTree is a frozen, read-only representation of a tree. Instead of
+// allocating every node, edge list and leaf separately it stores the whole
+// structure in a few contiguous arrays, which uses far less memory and is much
+// friendlier to the CPU cache for large trees that are mostly read.
+//
+// Nodes are laid out in breadth-first order so that the children of a node
+// are contiguous and sorted by label, similar to a LOUDS encoding. Keys are
+// not stored, they are rebuilt from the node prefixes during iteration.
+type CompactTree[T any] struct {
+	// nodes holds every node in breadth-first order, the root is at index 0.
+	nodes []compactNode
+
+	// prefixes holds the prefixes of all the nodes, back to back.
+	prefixes []byte
+
+	// values holds the leaf values in key order.
+	values []T
+}
+
+// compactNode is a single node in a CompactTree. The label of a node is the
+// first byte of its prefix.
+type compactNode struct {
+	// prefixOff and prefixLen locate the prefix of the node in prefixes.
+	prefixOff uint32
+	prefixLen uint32
+
+	// firstChild is the index in nodes of the first child, and numChildren
+	// is the number of contiguous children that follow it.
+	firstChild  uint32
+	numChildren uint32
+
+	// leaf is the index in values of the leaf value, or -1 if this node is
+	// not a leaf.
+	leaf int32
+}
+
+// Compact returns a frozen copy of the tree in the compact representation.
+// The tree itself is not modified and can still be used.
+func (t *Tree[T]) Compact() *CompactTree[T] {
+	c := &CompactTree[T]{
+		values: make([]T, 0, t.size),
+	}
+
+	// Leaf values are numbered in key order, so walk the tree first.
+	leaves := make(map[*leafNode[T]]int32, t.size)
+	recursiveWalkNodes(t.root, func(n *Node[T]) {
+		if n.leaf != nil {
+			leaves[n.leaf] = int32(len(c.values))
+			c.values = append(c.values, n.leaf.val)
+		}
+	})
+
+	// Then lay out the nodes breadth first.
+	queue := []*Node[T]{t.root}
+	for i := 0; i < len(queue); i++ {
+		n := queue[i]
+		cn := compactNode{
+			prefixOff:   uint32(len(c.prefixes)),
+			prefixLen:   uint32(len(n.prefix)),
+			firstChild:  uint32(len(queue)),
+			numChildren: uint32(len(n.edges)),
+			leaf:        -1,
+		}
+		c.prefixes = append(c.prefixes, n.prefix...)
+		if n.leaf != nil {
+			cn.leaf = leaves[n.leaf]
+		}
+		for _, e := range n.edges {
+			queue = append(queue, e.node)
+		}
+		c.nodes = append(c.nodes, cn)
+	}
+	return c
+}
+
+// recursiveWalkNodes calls fn for every node under n in key order.
+func recursiveWalkNodes[T any](n *Node[T], fn func(n *Node[T])) {
+	fn(n)
+	for _, e := range n.edges {
+		recursiveWalkNodes(e.node, fn)
+	}
+}
+
+// Len returns the number of elements in the tree.
+func (c *CompactTree[T]) Len() int {
+	return len(c.values)
+}
+
+// prefix returns the prefix of the node at the given index. The capacity is
+// capped so that appending to it can never overwrite the following prefixes.
+func (c *CompactTree[T]) prefix(idx uint32) []byte {
+	n := &c.nodes[idx]
+	end := n.prefixOff + n.prefixLen
+	return c.prefixes[n.prefixOff:end:end]
+}
+
+// child returns the index of the child of the given node with the given
+// label, and false if there is none.
+func (c *CompactTree[T]) child(idx uint32, label byte) (uint32, bool) {
+	n := &c.nodes[idx]
+	i := sort.Search(int(n.numChildren), func(i int) bool {
+		return c.prefixes[c.nodes[n.firstChild+uint32(i)].prefixOff] >= label
+	})
+	if i < int(n.numChildren) {
+		child := n.firstChild + uint32(i)
+		if c.prefixes[c.nodes[child].prefixOff] == label {
+			return child, true
+		}
+	}
+	return 0, false
+}
+
+// Get is used to lookup a specific key, returning
+// the value and if it was found
+func (c *CompactTree[T]) Get(k []byte) (T, bool) {
+	var zero T
+	idx := uint32(0)
+	search := k
+	if !bytes.HasPrefix(search, c.prefix(idx)) {
+		return zero, false
+	}
+	search = search[len(c.prefix(idx)):]
+	for {
+		// Check for key exhaustion
+		if len(search) == 0 {
+			if leaf := c.nodes[idx].leaf; leaf >= 0 {
+				return c.values[leaf], true
+			}
+			return zero, false
+		}
+
+		// Look for an edge
+		child, ok := c.child(idx, search[0])
+		if !ok {
+			return zero, false
+		}
+		idx = child
+
+		// Consume the search prefix
+		prefix := c.prefix(idx)
+		if !bytes.HasPrefix(search, prefix) {
+			return zero, false
+		}
+		search = search[len(prefix):]
+	}
+}
+
+// Walk is used to walk the tree in key order. The key passed to fn is a new
+// slice that the callback may keep.
+func (c *CompactTree[T]) Walk(fn WalkFn[T]) {
+	c.walk(0, c.prefix(0), fn)
+}
+
+// WalkPrefix is used to walk the tree under a prefix
+func (c *CompactTree[T]) WalkPrefix(prefix []byte, fn WalkFn[T]) {
+	idx := uint32(0)
+	path := append([]byte(nil), c.prefix(idx)...)
+	search := prefix
+	if !bytes.HasPrefix(search, path) {
+		if bytes.HasPrefix(path, search) {
+			c.walk(idx, path, fn)
+		}
+		return
+	}
+	search = search[len(path):]
+	for {
+		// Check for key exhaustion
+		if len(search) == 0 {
+			c.walk(idx, path, fn)
+			return
+		}
+
+		// Look for an edge
+		child, ok := c.child(idx, search[0])
+		if !ok {
+			return
+		}
+		idx = child
+		prefix := c.prefix(idx)
+		path = append(path, prefix...)
+
+		// Consume the search prefix
+		if bytes.HasPrefix(search, prefix) {
+			search = search[len(prefix):]
+		} else if bytes.HasPrefix(prefix, search) {
+			// Child may be under our search prefix
+			c.walk(idx, path, fn)
+			return
+		} else {
+			return
+		}
+	}
+}
+
+// walk does a pre-order walk of the node at the given index, whose full path
+// is path. Returns true if the walk should be aborted.
+func (c *CompactTree[T]) walk(idx uint32, path []byte, fn WalkFn[T]) bool {
+	n := c.nodes[idx]
+	if n.leaf >= 0 {
+		key := make([]byte, len(path))
+		copy(key, path)
+		if fn(key, c.values[n.leaf]) {
+			return true
+		}
+	}
+	for i := uint32(0); i < n.numChildren; i++ {
+		child := n.firstChild + i
+		if c.walk(child, append(path, c.prefix(child)...), fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tree converts the compact representation back to a regular mutable tree,
+// created with the given options.
+func (c *CompactTree[T]) Tree(opts ...Option[T]) *Tree[T] {
+	txn := New[T](opts...).Txn(false)
+	c.Walk(func(k []byte, v T) bool {
+		txn.Insert(k, v)
+		return false
+	})
+	return txn.Commit()
+}
@@ -0,0 +1,106 @@
+package iradix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+func TestCompactTree(t *testing.T) {
+	r := New[int]()
+	txn := r.Txn(false)
+	keys := []string{"", "foo", "foobar", "foo/bar", "foo/baz", "foo/zip", "zip", "zap"}
+	for i, k := range keys {
+		txn.Insert([]byte(k), i)
+	}
+	for i := 0; i < 1000; i++ {
+		gen, err := uuid.GenerateUUID()
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		txn.Insert([]byte(gen), i)
+	}
+	r = txn.Commit()
+
+	c := r.Compact()
+	if c.Len() != r.Len() {
+		t.Fatalf("bad len: %d", c.Len())
+	}
+
+	type kv struct {
+		k string
+		v int
+	}
+	collect := func(walk func(WalkFn[int])) []kv {
+		var out []kv
+		walk(func(k []byte, v int) bool {
+			out = append(out, kv{string(k), v})
+			return false
+		})
+		return out
+	}
+
+	all := collect(r.Root().Walk)
+	if got := collect(c.Walk); !reflect.DeepEqual(got, all) {
+		t.Fatalf("walk mismatch")
+	}
+	for _, e := range all {
+		if v, ok := c.Get([]byte(e.k)); !ok || v != e.v {
+			t.Fatalf("bad get %q: %v %v", e.k, v, ok)
+		}
+	}
+	for _, k := range []string{"f", "fo", "foo/", "foo/ba", "zi", "zipper", "\xff"} {
+		if _, ok := c.Get([]byte(k)); ok {
+			t.Fatalf("unexpected get %q", k)
+		}
+	}
+
+	for _, p := range []string{"", "f", "foo", "foo/", "foo/b", "foo/bar", "foo/barx", "z", "a", "0", "\xff"} {
+		want := collect(func(fn WalkFn[int]) { r.Root().WalkPrefix([]byte(p), fn) })
+		got := collect(func(fn WalkFn[int]) { c.WalkPrefix([]byte(p), fn) })
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("prefix %q mismatch: %v %v", p, got, want)
+		}
+	}
+
+	// Stopping the walk early.
+	n := 0
+	c.Walk(func(k []byte, v int) bool {
+		n++
+		return n == 3
+	})
+	if n != 3 {
+		t.Fatalf("walk did not stop: %d", n)
+	}
+
+	// Converting back gives an equivalent mutable tree.
+	r2 := c.Tree()
+	if r2.Len() != r.Len() {
+		t.Fatalf("bad len: %d", r2.Len())
+	}
+	if got := collect(r2.Root().Walk); !reflect.DeepEqual(got, all) {
+		t.Fatalf("walk mismatch after conversion")
+	}
+	r2, _, _ = r2.Insert([]byte("foo"), 42)
+	if v, _ := c.Get([]byte("foo")); v != 1 {
+		t.Fatalf("compact tree was modified")
+	}
+}
+
+func TestCompactTree_Empty(t *testing.T) {
+	c := New[int]().Compact()
+	if c.Len() != 0 {
+		t.Fatalf("bad len")
+	}
+	if _, ok := c.Get(nil); ok {
+		t.Fatalf("unexpected get")
+	}
+	c.Walk(func(k []byte, v int) bool {
+		t.Fatalf("unexpected walk")
+		return false
+	})
+	if c.Tree().Len() != 0 {
+		t.Fatalf("bad len")
+	}
+}
@@ -0,0 +1,201 @@
+package iradix
+
+import "errors"
+
+// Compressor is used to compress large leaf values. Adapters for snappy and
+// zstd are available in the compressors package, which is a module of its own
+// so that this one doesn't depend on them.
+type Compressor interface {
+	// Compress appends the compressed form of src to dst and returns it.
+	Compress(dst, src []byte) ([]byte, error)
+
+	// Decompress appends the decompressed form of src to dst and returns
+	// it.
+	Decompress(dst, src []byte) ([]byte, error)
+}
+
+const (
+	// compressedRaw and compressedPacked are the header bytes stored in
+	// front of every value in a CompressedTree, telling whether the value
+	// was compressed.
+	compressedRaw    byte = 0
+	compressedPacked byte = 1
+)
+
+// ErrCorruptValue is returned when a value stored in a CompressedTree can't
+// be decoded.
+var ErrCorruptValue = errors.New("corrupt compressed value")
+
+// CompressedTree is a tree of byte values that transparently compresses the
+// values that are at least threshold bytes long on insert, and decompresses
+// them on read. Smaller values, and values that don't shrink, are stored as
+// they are. Like Tree, it is immutable and safe for concurrent reads. The
+// values it returns are copies the caller owns.
+type CompressedTree struct {
+	tree       *Tree[[]byte]
+	compressor Compressor
+	threshold  int
+}
+
+// NewCompressed returns an empty CompressedTree using the given compressor for
+// values of at least threshold bytes.
+func NewCompressed(c Compressor, threshold int, opts ...Option[[]byte]) *CompressedTree {
+	return &CompressedTree{
+		tree:       New[[]byte](opts...),
+		compressor: c,
+		threshold:  threshold,
+	}
+}
+
+// Tree returns the underlying tree, whose values are in the encoded form.
+func (t *CompressedTree) Tree() *Tree[[]byte] {
+	return t.tree
+}
+
+// Len is used to return the number of elements in the tree
+func (t *CompressedTree) Len() int {
+	return t.tree.Len()
+}
+
+// encode returns the stored form of the given value.
+func (t *CompressedTree) encode(v []byte) ([]byte, error) {
+	if len(v) >= t.threshold {
+		packed, err := t.compressor.Compress([]byte{compressedPacked}, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(packed) < len(v)+1 {
+			return packed, nil
+		}
+	}
+	enc := make([]byte, len(v)+1)
+	enc[0] = compressedRaw
+	copy(enc[1:], v)
+	return enc, nil
+}
+
+// decode returns the original value from its stored form. The value returned
+// never shares memory with the stored form, which snapshots of the tree share
+// too, so callers may modify it.
+func (t *CompressedTree) decode(enc []byte) ([]byte, error) {
+	if len(enc) == 0 {
+		return nil, ErrCorruptValue
+	}
+	switch enc[0] {
+	case compressedRaw:
+		return append([]byte(nil), enc[1:]...), nil
+	case compressedPacked:
+		return t.compressor.Decompress(nil, enc[1:])
+	default:
+		return nil, ErrCorruptValue
+	}
+}
+
+// Get is used to lookup a specific key, returning the value and if it was
+// found.
+func (t *CompressedTree) Get(k []byte) ([]byte, bool, error) {
+	enc, ok := t.tree.Get(k)
+	if !ok {
+		return nil, false, nil
+	}
+	v, err := t.decode(enc)
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// Insert is used to add or update a given key. The return provides the new
+// tree and a bool indicating if the key was already set.
+func (t *CompressedTree) Insert(k, v []byte) (*CompressedTree, bool, error) {
+	txn := t.Txn()
+	ok, err := txn.Insert(k, v)
+	if err != nil {
+		return t, false, err
+	}
+	return txn.Commit(), ok, nil
+}
+
+// Delete is used to delete a given key. Returns the new tree and a bool
+// indicating if the key was set.
+func (t *CompressedTree) Delete(k []byte) (*CompressedTree, bool) {
+	txn := t.Txn()
+	ok := txn.Delete(k)
+	return txn.Commit(), ok
+}
+
+// WalkPrefix is used to walk the tree under a prefix, decoding every value.
+// The walk stops at the first value that can't be decoded and returns the
+// error.
+func (t *CompressedTree) WalkPrefix(prefix []byte, fn WalkFn[[]byte]) error {
+	var err error
+	t.tree.Root().WalkPrefix(prefix, func(k []byte, enc []byte) bool {
+		var v []byte
+		v, err = t.decode(enc)
+		if err != nil {
+			return true
+		}
+		return fn(k, v)
+	})
+	return err
+}
+
+// Walk is used to walk the whole tree, see WalkPrefix.
+func (t *CompressedTree) Walk(fn WalkFn[[]byte]) error {
+	return t.WalkPrefix(nil, fn)
+}
+
+// CompressedTxn is a transaction on a CompressedTree.
+type CompressedTxn struct {
+	txn  *Txn[[]byte]
+	tree *CompressedTree
+}
+
+// Txn starts a new transaction that can be used to mutate the tree
+func (t *CompressedTree) Txn() *CompressedTxn {
+	return &CompressedTxn{
+		txn:  t.tree.Txn(false),
+		tree: t,
+	}
+}
+
+// Get is used to lookup a specific key, returning the value and if it was
+// found.
+func (t *CompressedTxn) Get(k []byte) ([]byte, bool, error) {
+	enc, ok := t.txn.Get(k)
+	if !ok {
+		return nil, false, nil
+	}
+	v, err := t.tree.decode(enc)
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// Insert is used to add or update a given key, returning a bool indicating if
+// the key was already set.
+func (t *CompressedTxn) Insert(k, v []byte) (bool, error) {
+	enc, err := t.tree.encode(v)
+	if err != nil {
+		return false, err
+	}
+	_, ok := t.txn.Insert(k, enc)
+	return ok, nil
+}
+
+// Delete is used to delete a given key, returning a bool indicating if the key
+// was set.
+func (t *CompressedTxn) Delete(k []byte) bool {
+	_, ok := t.txn.Delete(k)
+	return ok
+}
+
+// Commit is used to finalize the transaction and return a new tree.
+func (t *CompressedTxn) Commit() *CompressedTree {
+	return &CompressedTree{
+		tree:       t.txn.Commit(),
+		compressor: t.tree.compressor,
+		threshold:  t.tree.threshold,
+	}
+}
@@ -0,0 +1,122 @@
+package iradix
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"testing"
+)
+
+// flateCompressor is a Compressor for tests, the adapters of the compressors
+// module aren't available here.
+type flateCompressor struct{}
+
+func (flateCompressor) Compress(dst, src []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	w, err := flate.NewWriter(buf, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (flateCompressor) Decompress(dst, src []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	if _, err := io.Copy(buf, flate.NewReader(bytes.NewReader(src))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func TestCompressedTree(t *testing.T) {
+	big := bytes.Repeat([]byte(`{"name":"foo","tags":["a","b"]}`), 100)
+	small := []byte("tiny")
+
+	r := NewCompressed(flateCompressor{}, 64)
+	r, ok, err := r.Insert([]byte("big"), big)
+	if err != nil || ok {
+		t.Fatalf("bad: %v %v", ok, err)
+	}
+	r, _, err = r.Insert([]byte("small"), small)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Big values are stored compressed, small ones as they are.
+	if enc, _ := r.Tree().Get([]byte("big")); len(enc) >= len(big) || enc[0] != compressedPacked {
+		t.Fatalf("big value not compressed: %d bytes", len(enc))
+	}
+	if enc, _ := r.Tree().Get([]byte("small")); enc[0] != compressedRaw {
+		t.Fatalf("small value compressed")
+	}
+
+	for k, want := range map[string][]byte{"big": big, "small": small} {
+		v, ok, err := r.Get([]byte(k))
+		if err != nil || !ok || !bytes.Equal(v, want) {
+			t.Fatalf("bad get %q: %v %v", k, ok, err)
+		}
+	}
+	if _, ok, err := r.Get([]byte("missing")); ok || err != nil {
+		t.Fatalf("bad: %v %v", ok, err)
+	}
+
+	var keys []string
+	err = r.Walk(func(k []byte, v []byte) bool {
+		keys = append(keys, string(k))
+		if len(v) != len(big) && len(v) != len(small) {
+			t.Fatalf("bad value for %q", k)
+		}
+		return false
+	})
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("bad walk: %v %v", keys, err)
+	}
+
+	txn := r.Txn()
+	if ok, _ := txn.Insert([]byte("big"), small); !ok {
+		t.Fatalf("expected update")
+	}
+	if !txn.Delete([]byte("small")) {
+		t.Fatalf("expected delete")
+	}
+	r2 := txn.Commit()
+	if r2.Len() != 1 || r.Len() != 2 {
+		t.Fatalf("bad len: %d %d", r2.Len(), r.Len())
+	}
+	if v, _, _ := r2.Get([]byte("big")); !bytes.Equal(v, small) {
+		t.Fatalf("bad value")
+	}
+
+	// Values returned are copies, modifying them doesn't change the tree.
+	for _, k := range []string{"big", "small"} {
+		v, _, _ := r.Get([]byte(k))
+		for i := range v {
+			v[i] = 'X'
+		}
+		r.Walk(func(_ []byte, v []byte) bool {
+			for i := range v {
+				v[i] = 'Y'
+			}
+			return false
+		})
+		if v, _, _ := r.Get([]byte(k)); bytes.ContainsAny(v, "XY") {
+			t.Fatalf("%q changed through a returned value: %q", k, v)
+		}
+	}
+	if v, _, _ := r.Get([]byte("small")); !bytes.Equal(v, small) {
+		t.Fatalf("bad value: %q", v)
+	}
+
+	// Corrupt values are reported rather than returned.
+	tree, _, _ := r.Tree().Insert([]byte("bad"), []byte{compressedPacked, 0xff, 0xff})
+	r3 := &CompressedTree{tree: tree, compressor: flateCompressor{}, threshold: 64}
+	if _, _, err := r3.Get([]byte("bad")); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,58 @@
+// Package compressors provides adapters for common compression libraries to
+// the iradix.Compressor interface, for use with iradix.NewCompressed. The
+// interface is satisfied structurally, so this module doesn't depend on the
+// iradix module and works with any version of it.
+package compressors
+
+import (
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Snappy compresses values with snappy, which is very fast and gives a
+// moderate compression ratio.
+type Snappy struct{}
+
+// Compress implements iradix.Compressor.
+func (Snappy) Compress(dst, src []byte) ([]byte, error) {
+	return append(dst, snappy.Encode(nil, src)...), nil
+}
+
+// Decompress implements iradix.Compressor.
+func (Snappy) Decompress(dst, src []byte) ([]byte, error) {
+	out, err := snappy.Decode(nil, src)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, out...), nil
+}
+
+// Zstd compresses values with zstd, which is slower than snappy but gives a
+// much better compression ratio. It is safe for concurrent use.
+type Zstd struct {
+	enc *zstd.Encoder
+	dec *zstd.Decoder
+}
+
+// NewZstd returns a zstd compressor using the given compression level.
+func NewZstd(level zstd.EncoderLevel) (*Zstd, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Zstd{enc: enc, dec: dec}, nil
+}
+
+// Compress implements iradix.Compressor.
+func (z *Zstd) Compress(dst, src []byte) ([]byte, error) {
+	return z.enc.EncodeAll(src, dst), nil
+}
+
+// Decompress implements iradix.Compressor.
+func (z *Zstd) Decompress(dst, src []byte) ([]byte, error) {
+	return z.dec.DecodeAll(src, dst)
+}
@@ -0,0 +1,39 @@
+package compressors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressor is iradix.Compressor, which this module doesn't import so that it
+// has no dependency on the version of the iradix module.
+type compressor interface {
+	Compress(dst, src []byte) ([]byte, error)
+	Decompress(dst, src []byte) ([]byte, error)
+}
+
+func TestCompressors(t *testing.T) {
+	z, err := NewZstd(zstd.SpeedDefault)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	in := bytes.Repeat([]byte("hello world "), 100)
+	for name, c := range map[string]compressor{"snappy": Snappy{}, "zstd": z} {
+		packed, err := c.Compress([]byte{7}, in)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if packed[0] != 7 || len(packed) >= len(in) {
+			t.Fatalf("%s: bad compression", name)
+		}
+		out, err := c.Decompress(nil, packed[1:])
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(out, in) {
+			t.Fatalf("%s: round trip mismatch", name)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/absolutelightning/go-immutable-radix/compressors
+
+go 1.21
+
+require (
+	github.com/golang/snappy v0.0.4
+	github.com/klauspost/compress v1.17.11
+)
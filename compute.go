@@ -0,0 +1,111 @@
+package iradix
+
+import "bytes"
+
+// Compute is used to transform the value of a key in place: fn is called with
+// the current value, if any, and returns the new value or true to delete the
+// key. It returns the value the key ends up with and whether it exists, so a
+// read-modify-write needs neither a separate Get nor care to keep the two in
+// step. Changes rejected like Insert and Delete are aren't applied, see
+// TryCompute.
+func (t *Txn[T]) Compute(k []byte, fn func(old T, exists bool) (T, bool)) (T, bool) {
+	v, ok, _ := t.TryCompute(k, fn)
+	return v, ok
+}
+
+// TryCompute is like Compute, but returns the error that TryInsert or
+// TryDelete would return, in which case the key is left as it was and its
+// current value is returned. Deleting a missing key does nothing.
+//
+// fn sees the current value of the key in the transaction, including the
+// writes made earlier in it, not the value as of the start of the
+// transaction. The key is looked up once: updating an existing key rewrites
+// the nodes found on the way, without searching the tree again. Inserting a
+// missing key and deleting one still go through TryInsert and TryDelete.
+func (t *Txn[T]) TryCompute(k []byte, fn func(old T, exists bool) (T, bool)) (T, bool, error) {
+	path := t.leafPath(k)
+	var old T
+	exists := path != nil
+	if exists {
+		old = path[len(path)-1].node.leaf.val
+	}
+
+	v, del := fn(old, exists)
+	if del {
+		if !exists {
+			return v, false, nil
+		}
+		if _, _, err := t.TryDelete(k); err != nil {
+			return old, true, err
+		}
+		var zero T
+		return zero, false, nil
+	}
+	if !exists {
+		if _, _, err := t.TryInsert(k, v); err != nil {
+			return old, false, err
+		}
+		return v, true, nil
+	}
+
+	v, err := t.admitInsert(k, v)
+	if err != nil {
+		return old, true, err
+	}
+	t.updateLeaf(path, k, v)
+	t.changed = true
+	return v, true, nil
+}
+
+// pathStep is a node on the path to a key, and the index of the edge taken
+// from it towards the key.
+type pathStep[T any] struct {
+	node *Node[T]
+	idx  int
+}
+
+// leafPath returns the nodes from the root down to the node holding the leaf
+// for k, or nil if the key is missing.
+func (t *Txn[T]) leafPath(k []byte) []pathStep[T] {
+	var path []pathStep[T]
+	n := t.root
+	search := k
+	for {
+		// The references are pushed down like insert does, so that the
+		// nodes can be written on the way back up.
+		n.processLazyRefCount()
+		if len(search) == 0 {
+			if !n.isLeaf() {
+				return nil
+			}
+			return append(path, pathStep[T]{node: n})
+		}
+		idx, child := n.getEdge(search[0])
+		if child == nil || !bytes.HasPrefix(search, child.prefix) {
+			return nil
+		}
+		path = append(path, pathStep[T]{node: n, idx: idx})
+		search = search[len(child.prefix):]
+		n = child
+	}
+}
+
+// updateLeaf replaces the leaf at the end of the path found by leafPath with
+// one holding v, writing the nodes of the path from the bottom up like the
+// update of an existing key by insert.
+func (t *Txn[T]) updateLeaf(path []pathStep[T], k []byte, v T) {
+	depth := t.depth
+	last := len(path) - 1
+	t.depth = depth + last
+	nc := t.writeNode(path[last].node, true)
+	nc.leaf = t.newLeaf(k, v)
+	t.progress.created(0, t.depth)
+	for i := last - 1; i >= 0; i-- {
+		t.depth = depth + i
+		child := nc
+		nc = t.writeNode(path[i].node, false)
+		nc.edges[path[i].idx].node = child
+	}
+	t.depth = depth
+	t.root = nc
+}
@@ -0,0 +1,101 @@
+package iradix
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	r := New[int](WithMaxTxnMutations[int](3))
+	r, _, _ = r.Insert([]byte("a"), 1)
+	r, _, _ = r.Insert([]byte("b"), 2)
+
+	incr := func(old int, exists bool) (int, bool) {
+		if !exists {
+			return 100, false
+		}
+		return old + 1, false
+	}
+	del := func(int, bool) (int, bool) { return 0, true }
+
+	txn := r.Txn(false)
+	if v, ok := txn.Compute([]byte("a"), incr); !ok || v != 2 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if v, ok := txn.Compute([]byte("c"), incr); !ok || v != 100 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if v, ok := txn.Compute([]byte("b"), del); ok || v != 0 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if _, ok := txn.Compute([]byte("missing"), del); ok || txn.Len() != 2 {
+		t.Fatalf("bad: %v %d", ok, txn.Len())
+	}
+	if v, _ := txn.Get([]byte("a")); v != 2 {
+		t.Fatalf("bad: %d", v)
+	}
+
+	// The transaction is full now, so the value is left as it was.
+	v, ok, err := txn.TryCompute([]byte("a"), incr)
+	if !errors.Is(err, ErrMaxTxnMutations) || !ok || v != 2 {
+		t.Fatalf("bad: %d %v %v", v, ok, err)
+	}
+	v, ok, err = txn.TryCompute([]byte("c"), del)
+	if !errors.Is(err, ErrMaxTxnMutations) || !ok || v != 100 {
+		t.Fatalf("bad: %d %v %v", v, ok, err)
+	}
+	if _, ok := r.Get([]byte("c")); ok {
+		t.Fatalf("original tree changed")
+	}
+}
+
+func TestCompute_Update(t *testing.T) {
+	r := New[int](WithInsertionOrder[int]())
+	for i, k := range []string{"foo", "foo/bar", "foo/baz", "zip"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	rootWatch, _, _ := r.Root().GetWatch(nil)
+	fooWatch := r.Root().WalkPrefixWatch([]byte("foo/"), func([]byte, int) bool { return false })
+	leafWatch, _, _ := r.Root().GetWatch([]byte("foo/bar"))
+	otherWatch, _, _ := r.Root().GetWatch([]byte("zip"))
+
+	incr := func(old int, exists bool) (int, bool) { return old + 10, false }
+	txn := r.Txn(false)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("foo/baz"), 5)
+
+	// The callback sees the writes made earlier in the transaction.
+	if v, ok := txn.Compute([]byte("foo/baz"), incr); !ok || v != 15 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if v, ok := txn.Compute([]byte("foo/bar"), incr); !ok || v != 11 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if v, ok := txn.Compute([]byte("foo/bar"), incr); !ok || v != 21 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	nr := txn.Commit()
+
+	if !watchFired(rootWatch) || !watchFired(fooWatch) || !watchFired(leafWatch) {
+		t.Fatalf("the path to the key should be notified")
+	}
+	if watchFired(otherWatch) {
+		t.Fatalf("other keys shouldn't be notified")
+	}
+	if hasAnyClosedMutateCh(nr) {
+		t.Fatalf("bad")
+	}
+	if v, _ := r.Get([]byte("foo/bar")); v != 1 {
+		t.Fatalf("original tree changed: %d", v)
+	}
+	if v, _ := nr.Get([]byte("foo/bar")); v != 21 || nr.Len() != 4 {
+		t.Fatalf("bad: %d %d", v, nr.Len())
+	}
+	verifyTree(t, []string{"foo", "foo/bar", "foo/baz", "zip"}, nr)
+
+	// Updated keys move to the end of the insertion order.
+	if got := orderKeys(nr.OldestFirst()); !reflect.DeepEqual(got, []string{"foo", "zip", "foo/baz", "foo/bar"}) {
+		t.Fatalf("bad order: %q", got)
+	}
+}
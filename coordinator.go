@@ -0,0 +1,163 @@
+package iradix
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrConflict is returned when committing a MultiTxn if another one was
+// committed since it began.
+var ErrConflict = errors.New("trees changed since the transaction began")
+
+// ErrMultiTxnDone is returned when committing a MultiTxn a second time.
+var ErrMultiTxnDone = errors.New("transaction already committed")
+
+// Coordinator publishes the roots of several trees together, such as a
+// primary tree and its secondary indexes, so that readers always see a
+// consistent set of trees and writers update them all or none.
+type Coordinator struct {
+	snap atomic.Pointer[Snapshot]
+}
+
+// Snapshot is a consistent set of the trees of a Coordinator. Use the
+// TreeHandle of each tree to get it from the snapshot.
+type Snapshot struct {
+	trees []any
+}
+
+// TreeHandle identifies a tree of a Coordinator.
+type TreeHandle[T any] struct {
+	idx int
+}
+
+// NewCoordinator returns a Coordinator without any trees.
+func NewCoordinator() *Coordinator {
+	c := &Coordinator{}
+	c.snap.Store(&Snapshot{})
+	return c
+}
+
+// AddTree adds a tree to the coordinator, returning its handle. Trees should
+// be added before the coordinator is used, since this conflicts with any
+// MultiTxn in progress.
+func AddTree[T any](c *Coordinator, t *Tree[T]) TreeHandle[T] {
+	for {
+		old := c.snap.Load()
+		trees := make([]any, len(old.trees), len(old.trees)+1)
+		copy(trees, old.trees)
+		trees = append(trees, t)
+		if c.snap.CompareAndSwap(old, &Snapshot{trees: trees}) {
+			return TreeHandle[T]{idx: len(old.trees)}
+		}
+	}
+}
+
+// Snapshot returns the current set of trees.
+func (c *Coordinator) Snapshot() *Snapshot {
+	return c.snap.Load()
+}
+
+// Tree returns the handle's tree in the given snapshot.
+func (h TreeHandle[T]) Tree(s *Snapshot) *Tree[T] {
+	return s.trees[h.idx].(*Tree[T])
+}
+
+// MultiTxn is a transaction on several trees of a Coordinator. It is
+// committed in two phases: Prepare commits each of the transactions to new
+// trees, failing if any of them recorded an error, and Commit then publishes
+// all the new trees at once, failing if another MultiTxn was committed in the
+// meantime. A MultiTxn is not safe for concurrent use.
+type MultiTxn struct {
+	c    *Coordinator
+	base *Snapshot
+	txns map[int]*multiTxnEntry
+
+	prepared *Snapshot
+	done     bool
+}
+
+// multiTxnEntry holds the type specific operations on one of the transactions
+// of a MultiTxn.
+type multiTxnEntry struct {
+	txn    any
+	err    func() error
+	commit func() any
+	notify func()
+}
+
+// Begin starts a transaction on the trees of the coordinator, based on its
+// current snapshot.
+func (c *Coordinator) Begin() *MultiTxn {
+	return &MultiTxn{
+		c:    c,
+		base: c.snap.Load(),
+		txns: make(map[int]*multiTxnEntry),
+	}
+}
+
+// Base returns the snapshot the transaction is based on.
+func (m *MultiTxn) Base() *Snapshot {
+	return m.base
+}
+
+// Txn returns the transaction on the handle's tree within m, starting it on
+// first use.
+func (h TreeHandle[T]) Txn(m *MultiTxn) *Txn[T] {
+	if e, ok := m.txns[h.idx]; ok {
+		return e.txn.(*Txn[T])
+	}
+	txn := h.Tree(m.base).Txn(false)
+	m.txns[h.idx] = &multiTxnEntry{
+		txn:    txn,
+		err:    txn.Err,
+		commit: func() any { return txn.CommitOnly() },
+		notify: func() {
+			if txn.trackMutate {
+				txn.Notify()
+			}
+		},
+	}
+	return txn
+}
+
+// Prepare commits each of the transactions to new trees without publishing
+// them, returning the first error recorded by any of them. The transactions
+// must not be modified afterwards.
+func (m *MultiTxn) Prepare() error {
+	if m.prepared != nil {
+		return nil
+	}
+	for _, e := range m.txns {
+		if err := e.err(); err != nil {
+			return err
+		}
+	}
+	trees := make([]any, len(m.base.trees))
+	copy(trees, m.base.trees)
+	for idx, e := range m.txns {
+		trees[idx] = e.commit()
+	}
+	m.prepared = &Snapshot{trees: trees}
+	return nil
+}
+
+// Commit publishes all the new trees at once, preparing them first if needed.
+// It returns ErrConflict, publishing nothing, if the coordinator's trees were
+// changed since the transaction began. Notifications are issued for the
+// transactions with mutation tracking turned on.
+func (m *MultiTxn) Commit() error {
+	if m.done {
+		return ErrMultiTxnDone
+	}
+	if err := m.Prepare(); err != nil {
+		return err
+	}
+	if !m.c.snap.CompareAndSwap(m.base, m.prepared) {
+		return ErrConflict
+	}
+	m.done = true
+	for _, e := range m.txns {
+		e.notify()
+	}
+	return nil
+}
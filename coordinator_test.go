@@ -0,0 +1,68 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCoordinator(t *testing.T) {
+	c := NewCoordinator()
+	users := AddTree(c, New[string]())
+	byEmail := AddTree(c, New[string]())
+
+	addUser := func(m *MultiTxn, id, email string) {
+		users.Txn(m).Insert([]byte(id), email)
+		byEmail.Txn(m).Insert([]byte(email), id)
+	}
+
+	m := c.Begin()
+	addUser(m, "u1", "a@example.com")
+	if err := m.Prepare(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	// Nothing is published before Commit.
+	if users.Tree(c.Snapshot()).Len() != 0 {
+		t.Fatalf("prepared trees should not be visible")
+	}
+	if err := m.Commit(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := m.Commit(); !errors.Is(err, ErrMultiTxnDone) {
+		t.Fatalf("bad: %v", err)
+	}
+	snap := c.Snapshot()
+	if users.Tree(snap).Len() != 1 || byEmail.Tree(snap).Len() != 1 {
+		t.Fatalf("trees should be published together")
+	}
+
+	// Concurrent transactions conflict, and the loser publishes nothing.
+	m1, m2 := c.Begin(), c.Begin()
+	addUser(m1, "u2", "b@example.com")
+	addUser(m2, "u3", "c@example.com")
+	if err := m1.Commit(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := m2.Commit(); !errors.Is(err, ErrConflict) {
+		t.Fatalf("bad: %v", err)
+	}
+	snap = c.Snapshot()
+	if _, ok := users.Tree(snap).Get([]byte("u3")); ok {
+		t.Fatalf("conflicting transaction should not be published")
+	}
+	if users.Tree(snap).Len() != 2 || byEmail.Tree(snap).Len() != 2 {
+		t.Fatalf("bad lens")
+	}
+
+	// A transaction with an error fails to prepare.
+	c2 := NewCoordinator()
+	limited := AddTree(c2, New[int](WithMaxTxnMutations[int](1)))
+	m = c2.Begin()
+	limited.Txn(m).Insert([]byte("a"), 1)
+	limited.Txn(m).Insert([]byte("b"), 2)
+	if err := m.Commit(); !errors.Is(err, ErrMaxTxnMutations) {
+		t.Fatalf("bad: %v", err)
+	}
+	if limited.Tree(c2.Snapshot()).Len() != 0 {
+		t.Fatalf("nothing should be published")
+	}
+}
@@ -0,0 +1,19 @@
+package iradix
+
+// integer is the constraint for the values of counter trees.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Add adds delta to the counter stored under the key in the transaction,
+// starting from zero if the key is missing, and returns the new value. The
+// values are stored directly in the leaves, so counters aren't boxed. Like
+// Insert, it isn't applied if the key is rejected, see Txn.Err. The key is
+// looked up once, see Txn.Compute.
+func Add[N integer](txn *Txn[N], k []byte, delta N) N {
+	v, _ := txn.Compute(k, func(old N, _ bool) (N, bool) {
+		return old + delta, false
+	})
+	return v
+}
@@ -0,0 +1,33 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	txn := New[int64]().Txn(false)
+	if v := Add(txn, []byte("hits"), 3); v != 3 {
+		t.Fatalf("bad: %d", v)
+	}
+	if v := Add(txn, []byte("hits"), -1); v != 2 {
+		t.Fatalf("bad: %d", v)
+	}
+	r := txn.Commit()
+	if v, _ := r.Get([]byte("hits")); v != 2 {
+		t.Fatalf("bad: %d", v)
+	}
+
+	// Rejected adds leave the counter as it was.
+	txn = New[int64](WithMaxTxnMutations[int64](1)).Txn(false)
+	Add(txn, []byte("a"), 1)
+	if v := Add(txn, []byte("a"), 1); v != 1 || !errors.Is(txn.Err(), ErrMaxTxnMutations) {
+		t.Fatalf("bad: %d %v", v, txn.Err())
+	}
+
+	type small uint8
+	st := New[small]().Txn(false)
+	if v := Add(st, []byte("x"), 200); v != 200 {
+		t.Fatalf("bad: %d", v)
+	}
+}
@@ -0,0 +1,63 @@
+package iradix
+
+import "bytes"
+
+// DetachPrefix is like DeletePrefix, but returns the deleted subtree as the
+// root of a tree holding only the keys under the prefix, along with their
+// number, so that a namespace can be archived elsewhere without reading it
+// before the deletion. The returned root is immutable and shares its nodes
+// with the trees the transaction was started from. It returns nil and 0 if
+// no key is under the prefix or the deletion is rejected, see DeletePrefix.
+func (t *Txn[T]) DetachPrefix(prefix []byte) (*Node[T], int) {
+	// Find the subtree along with its full path from the root, since its
+	// own prefix is only the last edge.
+	n := t.root
+	var path []byte
+	search := prefix
+	for len(search) > 0 {
+		_, n = n.getEdge(search[0])
+		if n == nil {
+			return nil, 0
+		}
+		switch {
+		case bytes.HasPrefix(search, n.prefix):
+			search = search[len(n.prefix):]
+		case bytes.HasPrefix(n.prefix, search):
+			search = nil
+		default:
+			return nil, 0
+		}
+		path = append(path, n.prefix...)
+	}
+	n.processLazyRefCount()
+	if n.leaf == nil && len(n.edges) == 0 {
+		return nil, 0
+	}
+
+	// The deletion may clear the node in place, so its contents are copied
+	// first. Its descendants are left alone.
+	detached := &Node[T]{
+		prefix:   path,
+		leaf:     n.leaf,
+		refCount: 1,
+	}
+	if len(n.edges) != 0 {
+		detached.edges = make([]edge[T], len(n.edges))
+		copy(detached.edges, n.edges)
+	}
+
+	deleted, ok := t.deletePrefixCount(prefix)
+	if !ok {
+		return nil, 0
+	}
+
+	root := detached
+	if len(path) > 0 {
+		root = &Node[T]{
+			edges:    []edge[T]{{label: path[0], node: detached}},
+			refCount: 1,
+		}
+	}
+	root.settle(t.conf)
+	return root, deleted
+}
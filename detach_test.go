@@ -0,0 +1,80 @@
+package iradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDetachPrefix(t *testing.T) {
+	txn := New[int](WithSizer[int](func(v int) int { return v })).Txn(false)
+	for i := 0; i < 50; i++ {
+		txn.Insert([]byte(fmt.Sprintf("ns%d/key%02d", i%3, i)), i)
+	}
+	txn.Insert([]byte("ns"), 100)
+	r := txn.Commit()
+
+	txn = r.Txn(false)
+	// Modified in place by the transaction before being detached.
+	txn.Insert([]byte("ns1/extra"), 1000)
+
+	root, n := txn.DetachPrefix([]byte("ns1/"))
+	if n != 18 || root == nil {
+		t.Fatalf("bad: %v %d", root, n)
+	}
+	txn.Insert([]byte("ns1/after"), 7)
+	r2 := txn.Commit()
+
+	var size int64
+	count := 0
+	root.Walk(func(k []byte, v int) bool {
+		if got, ok := r.Root().Get(k); (!ok || got != v) && string(k) != "ns1/extra" {
+			t.Fatalf("bad detached key %q: %d", k, v)
+		}
+		size += int64(v)
+		count++
+		return false
+	})
+	if count != 18 || root.Size() != size {
+		t.Fatalf("bad detached tree: %d keys, size %d want %d", count, root.Size(), size)
+	}
+	if v, ok := root.Get([]byte("ns1/extra")); !ok || v != 1000 {
+		t.Fatalf("detached tree should be searchable by full key: %d %v", v, ok)
+	}
+	if _, ok := root.Get([]byte("ns1/after")); ok {
+		t.Fatalf("detached tree changed after detaching")
+	}
+
+	if r2.Len() != r.Len()-17+1 {
+		t.Fatalf("bad len: %d", r2.Len())
+	}
+	r2.Root().WalkPrefix([]byte("ns1/"), func(k []byte, _ int) bool {
+		if string(k) != "ns1/after" {
+			t.Fatalf("key left behind: %q", k)
+		}
+		return false
+	})
+
+	// A prefix ending mid-edge and the whole tree.
+	txn = r.Txn(false)
+	if root, n := txn.DetachPrefix([]byte("ns2/k")); n != 16 || countKeys(root) != 16 {
+		t.Fatalf("bad: %d", n)
+	}
+	if root, n := txn.DetachPrefix(nil); n != r.Len()-16 || countKeys(root) != n {
+		t.Fatalf("bad: %d", n)
+	}
+	if root, n := txn.DetachPrefix([]byte("zz")); root != nil || n != 0 {
+		t.Fatalf("nothing should be detached")
+	}
+	if r.Len() != 51 {
+		t.Fatalf("original tree changed")
+	}
+}
+
+func countKeys[T any](n *Node[T]) int {
+	count := 0
+	n.Walk(func([]byte, T) bool {
+		count++
+		return false
+	})
+	return count
+}
@@ -0,0 +1,198 @@
+package iradix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// PrefixHashes maps the full path of nodes in a hashed tree to their subtree
+// hash. It is the format used to exchange tree summaries between replicas so
+// they can find which parts of their trees differ, see DivergingPrefixes.
+type PrefixHashes map[string][]byte
+
+// PrefixHashes returns the hashes of the node covering the given prefix and of
+// its descendants, down to depth levels below it. A depth of zero only returns
+// the covering node. The result is empty if the tree isn't hashed, has
+// uncommitted changes or has nothing under the prefix.
+func (n *Node[T]) PrefixHashes(prefix []byte, depth int) PrefixHashes {
+	out := make(PrefixHashes)
+	var path []byte
+	search := prefix
+	for len(search) > 0 {
+		// Look for an edge
+		_, child := n.getEdge(search[0])
+		if child == nil {
+			return out
+		}
+		path = append(path, child.prefix...)
+		n = child
+
+		// Consume the search prefix
+		if bytes.HasPrefix(search, n.prefix) {
+			search = search[len(n.prefix):]
+		} else if bytes.HasPrefix(n.prefix, search) {
+			break
+		} else {
+			return out
+		}
+	}
+	collectHashes(n, string(path), depth, out)
+	return out
+}
+
+// collectHashes adds the hashes of n and its descendants down to depth levels
+// into out.
+func collectHashes[T any](n *Node[T], path string, depth int, out PrefixHashes) {
+	if n.Hash() == nil {
+		return
+	}
+	out[path] = n.Hash()
+	if depth == 0 {
+		return
+	}
+	for _, e := range n.edges {
+		collectHashes(e.node, path+string(e.node.prefix), depth-1, out)
+	}
+}
+
+// DivergingPrefixes compares a hashed local tree with the hashes of a remote
+// replica and returns the smallest set of prefixes that have to be synced for
+// the two trees to agree. Subtrees whose hashes match are skipped entirely, so
+// the work done is proportional to the difference between the trees.
+//
+// The remote hashes are usually built in rounds: starting from a shallow
+// PrefixHashes of the remote root, each round asks the remote for the
+// PrefixHashes under the prefixes returned by the previous one and merges them
+// into the same map, until the returned prefixes are small enough to be
+// transferred whole.
+func DivergingPrefixes[T any](localRoot *Node[T], remote PrefixHashes) [][]byte {
+	// Sort the remote paths so that we can find the entries under a given
+	// path with a binary search.
+	paths := make([]string, 0, len(remote))
+	for p := range remote {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var out []string
+	var diverge func(n *Node[T], path string)
+	diverge = func(n *Node[T], path string) {
+		if h, ok := remote[path]; ok && n.Hash() != nil && bytes.Equal(h, n.Hash()) {
+			return
+		}
+
+		// Find the remote entries strictly under this path.
+		start := sort.SearchStrings(paths, path)
+		end := start
+		for end < len(paths) && strings.HasPrefix(paths[end], path) {
+			end++
+		}
+		if end > start && paths[start] == path {
+			start++
+		}
+
+		// If the remote has no finer information then the whole prefix
+		// needs to be synced.
+		if start == end {
+			out = append(out, path)
+			return
+		}
+
+		// Otherwise descend into our children, and report anything the
+		// remote has that doesn't belong to one of them.
+		var childPaths []string
+		for _, e := range n.edges {
+			childPath := path + string(e.node.prefix)
+			childPaths = append(childPaths, childPath)
+			diverge(e.node, childPath)
+		}
+		for _, p := range paths[start:end] {
+			covered := false
+			for _, cp := range childPaths {
+				if strings.HasPrefix(p, cp) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				out = append(out, p)
+			}
+		}
+	}
+	diverge(localRoot, string(localRoot.prefix))
+
+	// Drop prefixes covered by shorter ones, which are already sorted
+	// before them.
+	sort.Strings(out)
+	var result [][]byte
+	for _, p := range out {
+		if len(result) > 0 && strings.HasPrefix(p, string(result[len(result)-1])) {
+			continue
+		}
+		result = append(result, []byte(p))
+	}
+	return result
+}
+
+// ErrInvalidPrefixHashes is returned when decoding malformed PrefixHashes.
+var ErrInvalidPrefixHashes = errors.New("invalid prefix hashes encoding")
+
+// MarshalBinary encodes the hashes in a compact format suitable for sending to
+// another replica. Entries are written in path order so that the encoding is
+// deterministic.
+func (p PrefixHashes) MarshalBinary() ([]byte, error) {
+	paths := make([]string, 0, len(p))
+	for path := range p {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	buf := binary.AppendUvarint(nil, uint64(len(paths)))
+	for _, path := range paths {
+		buf = binary.AppendUvarint(buf, uint64(len(path)))
+		buf = append(buf, path...)
+		buf = binary.AppendUvarint(buf, uint64(len(p[path])))
+		buf = append(buf, p[path]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes hashes written by MarshalBinary, adding them to p.
+func (p *PrefixHashes) UnmarshalBinary(data []byte) error {
+	readBytes := func() ([]byte, bool) {
+		l, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < l {
+			return nil, false
+		}
+		b := data[n : n+int(l)]
+		data = data[n+int(l):]
+		return b, true
+	}
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return ErrInvalidPrefixHashes
+	}
+	data = data[n:]
+	if *p == nil {
+		*p = make(PrefixHashes)
+	}
+	for i := uint64(0); i < count; i++ {
+		path, ok := readBytes()
+		if !ok {
+			return ErrInvalidPrefixHashes
+		}
+		hash, ok := readBytes()
+		if !ok {
+			return ErrInvalidPrefixHashes
+		}
+		(*p)[string(path)] = append([]byte(nil), hash...)
+	}
+	if len(data) != 0 {
+		return ErrInvalidPrefixHashes
+	}
+	return nil
+}
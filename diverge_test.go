@@ -0,0 +1,120 @@
+package iradix
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// syncPrefixes copies the given prefixes from remote into local.
+func syncPrefixes(local, remote *Tree[int], prefixes [][]byte) *Tree[int] {
+	txn := local.Txn(false)
+	for _, p := range prefixes {
+		txn.DeletePrefix(p)
+		remote.Root().WalkPrefix(p, func(k []byte, v int) bool {
+			txn.Insert(k, v)
+			return false
+		})
+	}
+	return txn.Commit()
+}
+
+func TestDivergingPrefixes(t *testing.T) {
+	local := New[int](WithLeafHash(testLeafHash))
+	txn := local.Txn(false)
+	for i := 0; i < 1000; i++ {
+		txn.Insert([]byte(fmt.Sprintf("svc/%03d/node", i)), i)
+	}
+	local = txn.Commit()
+
+	txn = local.Txn(false)
+	txn.Insert([]byte("svc/010/node"), -1)
+	txn.Delete([]byte("svc/500/node"))
+	txn.Insert([]byte("svc/999/extra"), 1)
+	txn.Insert([]byte("other"), 1)
+	remote := txn.Commit()
+
+	// Identical trees don't diverge.
+	if d := DivergingPrefixes(local.Root(), local.Root().PrefixHashes(nil, 100)); len(d) != 0 {
+		t.Fatalf("unexpected divergence: %q", d)
+	}
+
+	// With only the root hash everything has to be synced.
+	d := DivergingPrefixes(local.Root(), remote.Root().PrefixHashes(nil, 0))
+	if !reflect.DeepEqual(d, [][]byte{{}}) {
+		t.Fatalf("bad: %q", d)
+	}
+
+	// With the full set of hashes we only get the differences.
+	d = DivergingPrefixes(local.Root(), remote.Root().PrefixHashes(nil, 100))
+	for _, p := range d {
+		for _, k := range []string{"svc/010/node", "svc/500/node", "svc/999/extra", "other"} {
+			if bytes.HasPrefix([]byte(k), p) {
+				goto OK
+			}
+		}
+		t.Fatalf("prefix %q doesn't cover a changed key", p)
+	OK:
+	}
+	synced := syncPrefixes(local, remote, d)
+	if !bytes.Equal(synced.RootHash(), remote.RootHash()) {
+		t.Fatalf("trees differ after sync of %q", d)
+	}
+
+	// Drill down in rounds, only asking for hashes under diverging prefixes.
+	hashes := remote.Root().PrefixHashes(nil, 1)
+	for round := 0; ; round++ {
+		if round > 10 {
+			t.Fatalf("too many rounds")
+		}
+		d = DivergingPrefixes(local.Root(), hashes)
+		grew := false
+		for _, p := range d {
+			for k, v := range remote.Root().PrefixHashes(p, 1) {
+				if _, ok := hashes[k]; !ok {
+					hashes[k] = v
+					grew = true
+				}
+			}
+		}
+		if !grew {
+			break
+		}
+	}
+	// Only the children of diverging nodes are exchanged: the root and its
+	// 2 children, the 10 children of svc/, then 10 children for each of the
+	// 3 changed svc keys at the next two levels, and at most 2 below those.
+	if len(hashes) > 3+10+3*10+3*10+3*2 {
+		t.Fatalf("exchanged too many hashes: %d", len(hashes))
+	}
+	synced = syncPrefixes(local, remote, d)
+	if !bytes.Equal(synced.RootHash(), remote.RootHash()) {
+		t.Fatalf("trees differ after sync of %q", d)
+	}
+}
+
+func TestPrefixHashes_Binary(t *testing.T) {
+	r := New[int](WithLeafHash(testLeafHash))
+	for i, k := range []string{"foo", "foobar", "foo/bar", "zip"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	in := r.Root().PrefixHashes(nil, 10)
+	if len(in) == 0 {
+		t.Fatalf("no hashes")
+	}
+	buf, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var out PrefixHashes
+	if err := out.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("bad: %v", out)
+	}
+	if err := out.UnmarshalBinary(buf[:len(buf)-1]); err != ErrInvalidPrefixHashes {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package iradix
+
+import "math/bits"
+
+// labelBitmapMinEdges is the number of edges from which a node keeps a
+// labelBitmap. Below it, a binary search of the edges is about as fast and
+// the bitmap isn't worth its memory.
+const labelBitmapMinEdges = 16
+
+// labelBitmap is a 256 bit set of the edge labels of a node. A miss is found
+// with a single bit test, and since the edges are sorted by label, the index
+// of a label's edge is the number of labels below it, so hits don't need a
+// binary search either.
+type labelBitmap [4]uint64
+
+// has returns true if the label is in the set.
+func (b *labelBitmap) has(label byte) bool {
+	return b[label>>6]&(1<<(label&63)) != 0
+}
+
+// rank returns the number of labels in the set below the given one.
+func (b *labelBitmap) rank(label byte) int {
+	word := int(label >> 6)
+	r := bits.OnesCount64(b[word] & (1<<(label&63) - 1))
+	for i := 0; i < word; i++ {
+		r += bits.OnesCount64(b[i])
+	}
+	return r
+}
+
+// updateLabels sets the label bitmap of a node being settled, whose edges
+// can no longer change, if it has enough edges to need one.
+func (n *Node[T]) updateLabels() {
+	if len(n.edges) < labelBitmapMinEdges {
+		n.labels = nil
+		return
+	}
+	var b labelBitmap
+	for _, e := range n.edges {
+		b[e.label>>6] |= 1 << (e.label & 63)
+	}
+	n.labels = &b
+}
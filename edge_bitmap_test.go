@@ -0,0 +1,75 @@
+package iradix
+
+import "testing"
+
+func TestLabelBitmap(t *testing.T) {
+	r := New[int]()
+	txn := r.Txn(false)
+	for i := 0; i < 256; i += 3 {
+		txn.Insert([]byte{byte(i)}, i)
+		txn.Insert([]byte{'x', byte(i)}, i)
+	}
+	txn.Insert([]byte("y1"), 1)
+	txn.Insert([]byte("y2"), 2)
+	r = txn.Commit()
+
+	check := func(n *Node[int]) {
+		t.Helper()
+		search := &Node[int]{edges: n.edges}
+		for l := 0; l < 256; l++ {
+			idx, child := n.getEdge(byte(l))
+			wantIdx, wantChild := search.getEdge(byte(l))
+			if idx != wantIdx || child != wantChild {
+				t.Fatalf("getEdge(%d): got %d, want %d", l, idx, wantIdx)
+			}
+			idx, child = n.getLowerBoundEdge(byte(l))
+			wantIdx, wantChild = search.getLowerBoundEdge(byte(l))
+			if idx != wantIdx || child != wantChild {
+				t.Fatalf("getLowerBoundEdge(%d): got %d, want %d", l, idx, wantIdx)
+			}
+		}
+	}
+
+	root := r.Root()
+	if root.labels == nil {
+		t.Fatalf("high fanout node should have a bitmap")
+	}
+	check(root)
+	_, x := root.getEdge('x')
+	if x.labels == nil {
+		t.Fatalf("high fanout node should have a bitmap")
+	}
+	check(x)
+	if _, y := root.getEdge('y'); y.labels != nil {
+		t.Fatalf("low fanout node should not have a bitmap")
+	}
+
+	// Modified nodes get a fresh bitmap on commit.
+	txn = r.Txn(false)
+	txn.Insert([]byte{'x', 1}, 1)
+	txn.Delete([]byte{'x', 0})
+	if v, ok := txn.Get([]byte{'x', 1}); !ok || v != 1 {
+		t.Fatalf("uncommitted insert not found")
+	}
+	r2 := txn.Commit()
+	_, x2 := r2.Root().getEdge('x')
+	if x2.labels == nil || x2.labels.has(0) || !x2.labels.has(1) {
+		t.Fatalf("bad bitmap after commit")
+	}
+	check(x2)
+	if _, ok := r.Get([]byte{'x', 0}); !ok {
+		t.Fatalf("original tree changed")
+	}
+}
+
+func BenchmarkGetEdge_Miss(b *testing.B) {
+	txn := New[int]().Txn(false)
+	for i := 0; i < 256; i += 2 {
+		txn.Insert([]byte{byte(i)}, i)
+	}
+	root := txn.Commit().Root()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root.getEdge(byte(i) | 1)
+	}
+}
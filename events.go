@@ -0,0 +1,160 @@
+package iradix
+
+import (
+	"bytes"
+	"context"
+)
+
+// EventOp is the kind of change an Event reports. The kinds are bit flags so
+// that an EventFilter can select several.
+type EventOp uint8
+
+const (
+	// EventInsert is reported for keys added to the tree.
+	EventInsert EventOp = 1 << iota
+
+	// EventUpdate is reported for keys whose value was replaced.
+	EventUpdate
+
+	// EventDelete is reported for keys removed from the tree.
+	EventDelete
+)
+
+// Event is a change of a key between two versions of a tree. Old is set for
+// updates and deletes, and New for inserts and updates.
+type Event[T any] struct {
+	Op       EventOp
+	Key      []byte
+	Old, New T
+}
+
+// EventFilter selects the events reported by Events. A nil Prefix selects all
+// the keys and zero Ops all the kinds of events.
+type EventFilter struct {
+	Prefix []byte
+	Ops    EventOp
+}
+
+// Events returns the changes between two versions of a tree, typically the
+// roots before and after a commit, as a sequence of events in key order. Since
+// the subtrees a commit didn't touch are shared between the versions, they
+// are skipped without being visited, so the cost depends on the size of the
+// change rather than of the tree. An update is reported for every key whose
+// leaf was replaced, even if it was set to the same value.
+func Events[T any](old, new *Node[T], f EventFilter) func(yield func(Event[T]) bool) {
+	return func(yield func(Event[T]) bool) {
+		var a, b diffSide[T]
+		a.start(old, f.Prefix)
+		b.start(new, f.Prefix)
+		emit := func(e Event[T]) bool {
+			return (f.Ops != 0 && f.Ops&e.Op == 0) || yield(e)
+		}
+
+		for {
+			na, nb := a.front(), b.front()
+			switch {
+			case na == nil && nb == nil:
+				return
+			case na == nb:
+				// Shared subtrees hold the same keys and values
+				a.pop()
+				b.pop()
+				continue
+			case na != nil && na.leaf == nil:
+				a.expand()
+				continue
+			case nb != nil && nb.leaf == nil:
+				b.expand()
+				continue
+			}
+
+			// Both fronts are leaves, or one side is done
+			cmp := -1
+			if na == nil {
+				cmp = 1
+			} else if nb != nil {
+				cmp = bytes.Compare(na.leaf.key, nb.leaf.key)
+			}
+			var ok bool
+			switch {
+			case cmp < 0:
+				ok = emit(Event[T]{Op: EventDelete, Key: na.leaf.key, Old: na.leaf.val})
+				a.expand()
+			case cmp > 0:
+				ok = emit(Event[T]{Op: EventInsert, Key: nb.leaf.key, New: nb.leaf.val})
+				b.expand()
+			default:
+				ok = true
+				if na.leaf != nb.leaf {
+					ok = emit(Event[T]{Op: EventUpdate, Key: na.leaf.key, Old: na.leaf.val, New: nb.leaf.val})
+				}
+				a.expand()
+				b.expand()
+			}
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// diffSide walks one of the trees compared by Events in key order.
+type diffSide[T any] struct {
+	stack []edges[T]
+}
+
+// start sets the walk to the subtree under the prefix.
+func (d *diffSide[T]) start(n *Node[T], prefix []byte) {
+	if n = n.prefixNode(prefix); n != nil {
+		d.stack = []edges[T]{{edge[T]{node: n}}}
+	}
+}
+
+// front returns the next node of the walk, or nil once it is done.
+func (d *diffSide[T]) front() *Node[T] {
+	if len(d.stack) == 0 {
+		return nil
+	}
+	return d.stack[len(d.stack)-1][0].node
+}
+
+// pop skips the next node of the walk along with its subtree.
+func (d *diffSide[T]) pop() {
+	n := len(d.stack)
+	if last := d.stack[n-1]; len(last) > 1 {
+		d.stack[n-1] = last[1:]
+	} else {
+		d.stack = d.stack[:n-1]
+	}
+}
+
+// expand replaces the next node of the walk by its children, which skips its
+// leaf.
+func (d *diffSide[T]) expand() {
+	elem := d.front()
+	d.pop()
+	if len(elem.edges) > 0 {
+		d.stack = append(d.stack, elem.edges)
+	}
+}
+
+// EventChan runs a sequence of events, such as the one returned by Events, in
+// a goroutine that sends them on the returned channel, which holds up to
+// buffer events and is closed once they have all been sent or the context is
+// done. The context has to be cancelled if the channel isn't drained, so that
+// the goroutine exits.
+func EventChan[T any](ctx context.Context, events func(yield func(Event[T]) bool), buffer int) <-chan Event[T] {
+	ch := make(chan Event[T], buffer)
+	go func() {
+		defer close(ch)
+		events(func(e Event[T]) bool {
+			select {
+			case ch <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch
+}
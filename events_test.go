@@ -0,0 +1,135 @@
+package iradix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// naiveEvents diffs two trees by walking all of their keys.
+func naiveEvents(old, new *Tree[int], f EventFilter) []Event[int] {
+	var events []Event[int]
+	add := func(e Event[int]) {
+		if bytes.HasPrefix(e.Key, f.Prefix) && (f.Ops == 0 || f.Ops&e.Op != 0) {
+			events = append(events, e)
+		}
+	}
+	ia, ib := old.Root().Iterator(), new.Root().Iterator()
+	ka, va, oka := ia.Next()
+	kb, vb, okb := ib.Next()
+	for oka || okb {
+		cmp := 0
+		switch {
+		case !okb:
+			cmp = -1
+		case !oka:
+			cmp = 1
+		default:
+			cmp = bytes.Compare(ka, kb)
+		}
+		switch {
+		case cmp < 0:
+			add(Event[int]{Op: EventDelete, Key: ka, Old: va})
+			ka, va, oka = ia.Next()
+		case cmp > 0:
+			add(Event[int]{Op: EventInsert, Key: kb, New: vb})
+			kb, vb, okb = ib.Next()
+		default:
+			if va != vb {
+				add(Event[int]{Op: EventUpdate, Key: ka, Old: va, New: vb})
+			}
+			ka, va, oka = ia.Next()
+			kb, vb, okb = ib.Next()
+		}
+	}
+	return events
+}
+
+func collectEvents(seq func(yield func(Event[int]) bool)) []Event[int] {
+	var events []Event[int]
+	seq(func(e Event[int]) bool {
+		events = append(events, e)
+		return true
+	})
+	return events
+}
+
+func TestEvents(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	r := New[int]()
+	val := 0
+	for round := 0; round < 50; round++ {
+		txn := r.Txn(false)
+		for i := 0; i < 1+rnd.Intn(20); i++ {
+			k := []byte(fmt.Sprintf("%c%d", 'a'+rnd.Intn(3), rnd.Intn(50)))
+			switch rnd.Intn(4) {
+			case 0:
+				txn.Delete(k)
+			case 1:
+				txn.DeletePrefix(k[:1+rnd.Intn(len(k))])
+			default:
+				val++
+				txn.Insert(k, val)
+			}
+		}
+		nr := txn.Commit()
+
+		for _, f := range []EventFilter{
+			{},
+			{Prefix: []byte("b")},
+			{Prefix: []byte("c1")},
+			{Ops: EventInsert | EventDelete},
+			{Prefix: []byte("a"), Ops: EventUpdate},
+		} {
+			got := collectEvents(Events(r.Root(), nr.Root(), f))
+			if want := naiveEvents(r, nr, f); !reflect.DeepEqual(got, want) {
+				t.Fatalf("round %d, filter %+v:\ngot  %v\nwant %v", round, f, got, want)
+			}
+		}
+		r = nr
+	}
+
+	if events := collectEvents(Events(r.Root(), r.Root(), EventFilter{})); len(events) != 0 {
+		t.Fatalf("same tree should have no events: %v", events)
+	}
+
+	// Stopping early.
+	n := 0
+	Events(New[int]().Root(), r.Root(), EventFilter{})(func(Event[int]) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Fatalf("should stop: %d", n)
+	}
+}
+
+func TestEventChan(t *testing.T) {
+	r := New[int]()
+	for i := 0; i < 10; i++ {
+		r, _, _ = r.Insert([]byte(fmt.Sprintf("k%d", i)), i)
+	}
+	seq := Events(New[int]().Root(), r.Root(), EventFilter{})
+
+	n := 0
+	for e := range EventChan(context.Background(), seq, 2) {
+		if e.Op != EventInsert || e.New != n {
+			t.Fatalf("bad event: %+v", e)
+		}
+		n++
+	}
+	if n != 10 {
+		t.Fatalf("bad: %d", n)
+	}
+
+	// Cancelling closes the channel without draining it.
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := EventChan(ctx, seq, 0)
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
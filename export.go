@@ -0,0 +1,111 @@
+package iradix
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrCorruptExport is returned by ReadExport for a stream that can't be
+// decoded.
+var ErrCorruptExport = errors.New("corrupt export stream")
+
+// ExportOptions configures ExportPrefix.
+type ExportOptions[T any] struct {
+	// Encode encodes the values written.
+	Encode func(T) ([]byte, error)
+
+	// After resumes the export after this key. It is the continuation
+	// returned for the previous chunk, and nil for the first one.
+	After []byte
+
+	// MaxBytes bounds the size of the chunk written. A chunk always holds
+	// at least one entry, so one larger than MaxBytes is written on its
+	// own. Zero or less means no limit.
+	MaxBytes int
+}
+
+// ExportPrefix writes a chunk of the entries under the given prefix to w, in
+// key order, and returns a continuation key to pass as After to write the
+// next chunk, or nil once every entry was written. This lets tooling stream
+// huge namespaces out in bounded, resumable chunks; exporting from the same
+// root gives a consistent listing. Each entry is written as the uvarint length
+// of the key, the key, the uvarint length of the encoded value and the
+// encoded value, see ReadExport.
+func (n *Node[T]) ExportPrefix(prefix []byte, w io.Writer, opts ExportOptions[T]) ([]byte, error) {
+	next := pageIterator(n, PageOptions{Prefix: prefix, AfterKey: opts.After})
+	written := 0
+	var last []byte
+	var buf []byte
+	for {
+		k, v, ok := next()
+		if !ok || !bytes.HasPrefix(k, prefix) {
+			return nil, nil
+		}
+		if opts.After != nil && bytes.Equal(k, opts.After) {
+			continue
+		}
+
+		enc, err := opts.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf[:0], uint64(len(k)))
+		buf = append(buf, k...)
+		buf = binary.AppendUvarint(buf, uint64(len(enc)))
+		buf = append(buf, enc...)
+		if opts.MaxBytes > 0 && last != nil && written+len(buf) > opts.MaxBytes {
+			return last, nil
+		}
+		if _, err := w.Write(buf); err != nil {
+			return nil, err
+		}
+		written += len(buf)
+		last = k
+	}
+}
+
+// MaxExportLen is the largest key or value ReadExport accepts.
+const MaxExportLen = 1 << 30
+
+// ReadExport decodes the entries written by ExportPrefix from r, calling fn
+// for each of them until the end of the stream. An error returned by fn stops
+// the decoding and is returned. Keys and values longer than MaxExportLen are
+// rejected with ErrCorruptExport, and the memory used to read the others only
+// grows with the data actually read, so a corrupt length can't make it
+// allocate more than the stream holds.
+func ReadExport(r io.Reader, fn func(k, v []byte) error) error {
+	br := bufio.NewReader(r)
+	readBytes := func() ([]byte, error) {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		if n > MaxExportLen {
+			return nil, ErrCorruptExport
+		}
+		b, err := io.ReadAll(io.LimitReader(br, int64(n)))
+		if err != nil || uint64(len(b)) != n {
+			return nil, ErrCorruptExport
+		}
+		return b, nil
+	}
+	for {
+		k, err := readBytes()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return ErrCorruptExport
+		}
+		v, err := readBytes()
+		if err != nil {
+			return ErrCorruptExport
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package iradix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestExportPrefix(t *testing.T) {
+	txn := New[int]().Txn(false)
+	for i := 0; i < 100; i++ {
+		txn.Insert([]byte(fmt.Sprintf("ns/%03d", i)), i)
+	}
+	txn.Insert([]byte("other"), -1)
+	txn.Insert([]byte("ns"), -2)
+	r := txn.Commit()
+
+	encode := func(v int) ([]byte, error) { return []byte(strconv.Itoa(v)), nil }
+	var chunks int
+	var got []string
+	var after []byte
+	for {
+		var buf bytes.Buffer
+		next, err := r.Root().ExportPrefix([]byte("ns/"), &buf, ExportOptions[int]{
+			Encode:   encode,
+			After:    after,
+			MaxBytes: 100,
+		})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if buf.Len() > 100 {
+			t.Fatalf("chunk too big: %d", buf.Len())
+		}
+		chunks++
+		err = ReadExport(&buf, func(k, v []byte) error {
+			got = append(got, string(k)+"="+string(v))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if next == nil {
+			break
+		}
+		after = next
+	}
+	if len(got) != 100 || got[0] != "ns/000=0" || got[99] != "ns/099=99" {
+		t.Fatalf("bad export: %d %v", len(got), got)
+	}
+	if chunks < 10 {
+		t.Fatalf("expected several chunks: %d", chunks)
+	}
+
+	// A chunk always makes progress.
+	var buf bytes.Buffer
+	next, err := r.Root().ExportPrefix(nil, &buf, ExportOptions[int]{Encode: encode, MaxBytes: 1})
+	if err != nil || string(next) != "ns" {
+		t.Fatalf("bad: %q %v", next, err)
+	}
+
+	boom := errors.New("boom")
+	_, err = r.Root().ExportPrefix(nil, &buf, ExportOptions[int]{Encode: func(int) ([]byte, error) { return nil, boom }})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
+func TestReadExport_Corrupt(t *testing.T) {
+	r := New[int]()
+	r, _, _ = r.Insert([]byte("key"), 1)
+	var buf bytes.Buffer
+	encode := func(v int) ([]byte, error) { return []byte(strconv.Itoa(v)), nil }
+	if _, err := r.Root().ExportPrefix(nil, &buf, ExportOptions[int]{Encode: encode}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	valid := buf.Bytes()
+
+	huge := binary.AppendUvarint(nil, MaxExportLen+1)
+	lying := binary.AppendUvarint(nil, math.MaxUint64>>1)
+	cases := [][]byte{
+		{5, 'a'},
+		append(huge, 'a'),
+		append(lying, 'a', 'b'),
+		append(append([]byte{1, 'k'}, lying...), 'v'),
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+	// The stream holds a single entry, so every truncation of it is rejected.
+	for i := 1; i < len(valid); i++ {
+		cases = append(cases, valid[:i])
+	}
+	for _, c := range cases {
+		err := ReadExport(bytes.NewReader(c), func(k, v []byte) error {
+			t.Fatalf("stream %v: unexpected entry %q=%q", c, k, v)
+			return nil
+		})
+		if !errors.Is(err, ErrCorruptExport) {
+			t.Fatalf("stream %v: expected ErrCorruptExport, got %v", c, err)
+		}
+	}
+}
+
+func FuzzReadExport(f *testing.F) {
+	f.Add([]byte{3, 'k', 'e', 'y', 1, '1'})
+	f.Add([]byte{5, 'a'})
+	f.Add(binary.AppendUvarint(nil, MaxExportLen+1))
+	f.Fuzz(func(t *testing.T, stream []byte) {
+		err := ReadExport(bytes.NewReader(stream), func(k, v []byte) error {
+			if len(k)+len(v) > len(stream) {
+				t.Fatalf("entry larger than the stream")
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, ErrCorruptExport) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package iradix
+
+import "sync/atomic"
+
+// lastGeneration is the last generation stamped on a node, shared by all trees
+// so that generations are unique across them.
+var lastGeneration atomic.Uint64
+
+// nextGeneration returns a new, never used generation.
+func nextGeneration() uint64 {
+	return lastGeneration.Add(1)
+}
+
+// Generation returns the generation of the subtree rooted at n. Every commit
+// stamps the nodes it modified with new generations, and leaves the others
+// alone, so two committed nodes with the same generation hold the same
+// subtree, and a subtree's generation is unchanged by commits that didn't
+// touch it. This makes it a cheap key for memoizing results computed from a
+// subtree. Generations are unique across all trees, and are only meaningful
+// for committed trees, not for the nodes of a transaction in progress.
+func (n *Node[T]) Generation() uint64 {
+	return n.generation
+}
+
+// Generation returns the generation of the root of the tree, see
+// Node.Generation.
+func (t *Tree[T]) Generation() uint64 {
+	return t.root.generation
+}
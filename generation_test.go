@@ -0,0 +1,39 @@
+package iradix
+
+import "testing"
+
+func TestGeneration(t *testing.T) {
+	r := New[int]()
+	for _, k := range []string{"a/1", "a/2", "b/1", "b/2"} {
+		r, _, _ = r.Insert([]byte(k), 1)
+	}
+	if r.Generation() == 0 {
+		t.Fatalf("committed tree should have a generation")
+	}
+
+	// A commit without mutations keeps the generation.
+	noop := r.Txn(false).Commit()
+	if noop.Generation() != r.Generation() {
+		t.Fatalf("no-op commit changed the generation")
+	}
+
+	// A mutation changes the generation of the modified path only.
+	aGen := r.Root().prefixNode([]byte("a/")).Generation()
+	bGen := r.Root().prefixNode([]byte("b/")).Generation()
+	r2, _, _ := r.Insert([]byte("b/3"), 1)
+	if r2.Generation() == r.Generation() {
+		t.Fatalf("root generation should change")
+	}
+	if got := r2.Root().prefixNode([]byte("a/")).Generation(); got != aGen {
+		t.Fatalf("untouched subtree generation changed: %d != %d", got, aGen)
+	}
+	if got := r2.Root().prefixNode([]byte("b/")).Generation(); got == bGen {
+		t.Fatalf("modified subtree generation should change")
+	}
+
+	// Generations are unique across trees.
+	other, _, _ := New[int]().Insert([]byte("a/1"), 1)
+	if other.Generation() == r.Generation() || other.Generation() == r2.Generation() {
+		t.Fatalf("generations should be unique")
+	}
+}
@@ -0,0 +1,57 @@
+package iradix
+
+import "bytes"
+
+// PathElem is a node visited on the way down to a key, see GetPath.
+type PathElem[T any] struct {
+	// Prefix is the part of the key leading to the node, which is the key
+	// of its leaf if it has one.
+	Prefix []byte
+
+	// Segment is the part of the prefix matched by this node. It must not
+	// be modified.
+	Segment []byte
+
+	// HasLeaf is true if the node has a leaf, whose value is Value.
+	HasLeaf bool
+	Value   T
+}
+
+// GetPath returns every node visited on the way from n down to the given key
+// whose segment is fully matched, starting with n itself. The leaves among
+// them are all the keys that are prefixes of k, so a policy engine can
+// evaluate every ancestor rule rather than only the longest match. The bool
+// reports whether k itself is in the tree, in which case it is the last
+// element.
+func (n *Node[T]) GetPath(k []byte) ([]PathElem[T], bool) {
+	var path []PathElem[T]
+	search := k
+	for {
+		elem := PathElem[T]{
+			Prefix:  k[:len(k)-len(search)],
+			Segment: n.prefix,
+		}
+		if n.leaf != nil {
+			elem.HasLeaf = true
+			elem.Value = n.leaf.val
+		}
+		path = append(path, elem)
+
+		// Check for key exhaustion
+		if len(search) == 0 {
+			return path, n.leaf != nil
+		}
+
+		// Look for an edge
+		_, n = n.getEdge(search[0])
+		if n == nil {
+			return path, false
+		}
+
+		// Consume the search prefix
+		if !bytes.HasPrefix(search, n.prefix) {
+			return path, false
+		}
+		search = search[len(n.prefix):]
+	}
+}
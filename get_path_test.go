@@ -0,0 +1,53 @@
+package iradix
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestGetPath(t *testing.T) {
+	r := New[string]()
+	for _, k := range []string{"", "/api", "/api/v1/users", "/api/v2", "/static"} {
+		r, _, _ = r.Insert([]byte(k), "rule:"+k)
+	}
+
+	describe := func(path []PathElem[string]) []string {
+		var out []string
+		for _, e := range path {
+			if e.HasLeaf {
+				out = append(out, fmt.Sprintf("%s=%s", e.Prefix, e.Value))
+			}
+		}
+		return out
+	}
+
+	path, ok := r.Root().GetPath([]byte("/api/v1/users"))
+	if !ok {
+		t.Fatalf("should be found")
+	}
+	want := []string{"=rule:", "/api=rule:/api", "/api/v1/users=rule:/api/v1/users"}
+	if got := describe(path); !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if last := path[len(path)-1]; string(last.Prefix) != "/api/v1/users" {
+		t.Fatalf("bad last element: %q", last.Prefix)
+	}
+
+	// Every segment adds up to the prefix.
+	var joined []byte
+	for _, e := range path {
+		joined = append(joined, e.Segment...)
+		if string(joined) != string(e.Prefix) {
+			t.Fatalf("segments %q don't add up to %q", joined, e.Prefix)
+		}
+	}
+
+	path, ok = r.Root().GetPath([]byte("/api/v1/groups"))
+	if ok {
+		t.Fatalf("should not be found")
+	}
+	if got := describe(path); !slices.Equal(got, want[:2]) {
+		t.Fatalf("got %v", got)
+	}
+}
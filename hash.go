@@ -0,0 +1,207 @@
+package iradix
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// LeafHashFn is used to compute the content hash of a single leaf. The
+// returned digest should cover everything about the value that replicas
+// are expected to agree on.
+type LeafHashFn[T any] func(k []byte, v T) []byte
+
+// WithLeafHash enables content hashing of the tree. Every node keeps a hash
+// of its subtree, built from the leaf hashes returned by fn, which is updated
+// for the modified nodes when a transaction is committed. Two trees holding
+// the same keys and values have the same root hash regardless of the order
+// the keys were inserted in.
+func WithLeafHash[T any](fn LeafHashFn[T]) Option[T] {
+	return func(c *config[T]) {
+		c.leafHash = fn
+	}
+}
+
+// Hash returns the content hash of the subtree rooted at this node, or nil if
+// the tree is not hashed or the node was modified by a transaction that has
+// not been committed yet.
+func (n *Node[T]) Hash() []byte {
+	if n.agg == nil {
+		return nil
+	}
+	return n.agg.hash
+}
+
+// RootHash returns the content hash of the whole tree, or nil if the tree was
+// not created with WithLeafHash.
+func (t *Tree[T]) RootHash() []byte {
+	return t.root.Hash()
+}
+
+// updateHash computes the hash of the node from its leaf and the hashes of its
+// children, which must already be up to date.
+func (n *Node[T]) updateHash(fn LeafHashFn[T]) {
+	var leafHash []byte
+	if n.leaf != nil {
+		// Leaves are replaced rather than modified, so a hash computed for
+		// a leaf in an earlier commit is still valid.
+		if n.leaf.extra == nil {
+			n.leaf.extra = &leafExtra{}
+		}
+		if n.leaf.extra.hash == nil {
+			n.leaf.extra.hash = fn(n.leaf.key, n.leaf.val)
+		}
+		leafHash = n.leaf.extra.hash
+	}
+	proofEdges := make([]ProofEdge, len(n.edges))
+	for i, e := range n.edges {
+		proofEdges[i] = ProofEdge{Label: e.label, Hash: e.node.Hash()}
+	}
+	n.agg.hash = hashNode(n.prefix, n.leaf != nil, leafHash, proofEdges)
+}
+
+// hashNode computes the hash of a single node from its prefix, its leaf hash
+// and the hashes of its children.
+func hashNode(prefix []byte, isLeaf bool, leafHash []byte, edges []ProofEdge) []byte {
+	h := sha256.New()
+	var buf [binary.MaxVarintLen64]byte
+	writeBytes := func(b []byte) {
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(b)))])
+		h.Write(b)
+	}
+	writeBytes(prefix)
+	if isLeaf {
+		h.Write([]byte{1})
+		writeBytes(leafHash)
+	} else {
+		h.Write([]byte{0})
+	}
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(edges)))])
+	for _, e := range edges {
+		h.Write([]byte{e.Label})
+		writeBytes(e.Hash)
+	}
+	return h.Sum(nil)
+}
+
+// ProofEdge is the label and subtree hash of a single child in a Proof.
+type ProofEdge struct {
+	Label byte
+	Hash  []byte
+}
+
+// ProofNode holds everything needed to recompute the hash of one node along
+// the path of a Proof.
+type ProofNode struct {
+	// Prefix is the prefix of the node.
+	Prefix []byte
+
+	// IsLeaf is set if the node holds a leaf, and LeafHash is its hash.
+	IsLeaf   bool
+	LeafHash []byte
+
+	// Edges are the labels and hashes of all the children of the node.
+	Edges []ProofEdge
+
+	// Child is the index in Edges of the next node along the path, or -1
+	// for the last node in the proof.
+	Child int
+}
+
+// Proof is an inclusion proof for a single key, made of the nodes along the
+// path from the root down to the leaf.
+type Proof struct {
+	Nodes []ProofNode
+}
+
+// ProvePath returns an inclusion proof for the given key, and false if the
+// key is not in the tree or the tree is not hashed.
+func (n *Node[T]) ProvePath(k []byte) (*Proof, bool) {
+	if n.Hash() == nil {
+		return nil, false
+	}
+	proof := &Proof{}
+	search := k
+	for {
+		pn := ProofNode{
+			Prefix: n.prefix,
+			IsLeaf: n.leaf != nil,
+			Edges:  make([]ProofEdge, len(n.edges)),
+			Child:  -1,
+		}
+		for i, e := range n.edges {
+			pn.Edges[i] = ProofEdge{Label: e.label, Hash: e.node.Hash()}
+		}
+
+		// Check for key exhaustion
+		if len(search) == 0 {
+			if !n.isLeaf() {
+				return nil, false
+			}
+			pn.LeafHash = n.leaf.getHash()
+			proof.Nodes = append(proof.Nodes, pn)
+			return proof, true
+		}
+		if n.leaf != nil {
+			pn.LeafHash = n.leaf.getHash()
+		}
+
+		// Look for an edge
+		idx, child := n.getEdge(search[0])
+		if child == nil || !bytes.HasPrefix(search, child.prefix) {
+			return nil, false
+		}
+		pn.Child = idx
+		proof.Nodes = append(proof.Nodes, pn)
+
+		// Consume the search prefix
+		search = search[len(child.prefix):]
+		n = child
+	}
+}
+
+// Root returns the root hash implied by the proof for the given key and leaf
+// hash, and false if the proof is malformed or doesn't lead to the key.
+func (p *Proof) Root(k, leafHash []byte) ([]byte, bool) {
+	if len(p.Nodes) == 0 {
+		return nil, false
+	}
+
+	// Make sure the path spelled by the proof is the key.
+	var path []byte
+	for _, pn := range p.Nodes {
+		path = append(path, pn.Prefix...)
+	}
+	if !bytes.Equal(path, k) {
+		return nil, false
+	}
+
+	var hash []byte
+	for i := len(p.Nodes) - 1; i >= 0; i-- {
+		pn := p.Nodes[i]
+		if i == len(p.Nodes)-1 {
+			if pn.Child != -1 || !pn.IsLeaf {
+				return nil, false
+			}
+			pn.LeafHash = leafHash
+		} else {
+			next := p.Nodes[i+1]
+			if pn.Child < 0 || pn.Child >= len(pn.Edges) || len(next.Prefix) == 0 {
+				return nil, false
+			}
+			e := pn.Edges[pn.Child]
+			if e.Label != next.Prefix[0] || !bytes.Equal(e.Hash, hash) {
+				return nil, false
+			}
+		}
+		hash = hashNode(pn.Prefix, pn.IsLeaf, pn.LeafHash, pn.Edges)
+	}
+	return hash, true
+}
+
+// Verify returns true if the proof shows that the key with the given leaf
+// hash is part of the tree with the given root hash.
+func (p *Proof) Verify(rootHash, k, leafHash []byte) bool {
+	hash, ok := p.Root(k, leafHash)
+	return ok && bytes.Equal(hash, rootHash)
+}
@@ -0,0 +1,115 @@
+package iradix
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+)
+
+func testLeafHash(k []byte, v int) []byte {
+	h := sha256.New()
+	h.Write(k)
+	h.Write([]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+	return h.Sum(nil)
+}
+
+func TestRootHash(t *testing.T) {
+	keys := []string{"foo", "foobar", "foo/bar", "foo/baz", "zip", "zap", ""}
+
+	r1 := New[int](WithLeafHash(testLeafHash))
+	if r1.RootHash() == nil {
+		t.Fatalf("expected a hash for the empty tree")
+	}
+	empty := r1.RootHash()
+	for i, k := range keys {
+		r1, _, _ = r1.Insert([]byte(k), i)
+	}
+
+	// Insert in a different order in a single transaction.
+	r2 := New[int](WithLeafHash(testLeafHash))
+	txn := r2.Txn(false)
+	for _, i := range rand.Perm(len(keys)) {
+		txn.Insert([]byte(keys[i]), i)
+	}
+	r2 = txn.Commit()
+
+	if !bytes.Equal(r1.RootHash(), r2.RootHash()) {
+		t.Fatalf("hash mismatch for identical content")
+	}
+
+	// Changing a value must change the hash, and changing it back must
+	// restore it.
+	r3, _, _ := r2.Insert([]byte("foo/bar"), 42)
+	if bytes.Equal(r3.RootHash(), r2.RootHash()) {
+		t.Fatalf("hash did not change")
+	}
+	r3, _, _ = r3.Insert([]byte("foo/bar"), 2)
+	if !bytes.Equal(r3.RootHash(), r2.RootHash()) {
+		t.Fatalf("hash mismatch after restoring value")
+	}
+
+	// Deleting everything must take us back to the empty hash.
+	txn = r3.Txn(false)
+	txn.DeletePrefix([]byte("foo"))
+	for _, k := range keys {
+		txn.Delete([]byte(k))
+	}
+	r3 = txn.Commit()
+	if !bytes.Equal(r3.RootHash(), empty) {
+		t.Fatalf("expected empty hash")
+	}
+
+	// The original trees are unaffected.
+	if !bytes.Equal(r1.RootHash(), r2.RootHash()) {
+		t.Fatalf("hash of older tree changed")
+	}
+
+	// Trees without a hash function have no hash.
+	r4, _, _ := New[int]().Insert([]byte("foo"), 1)
+	if r4.RootHash() != nil {
+		t.Fatalf("unexpected hash")
+	}
+	if _, ok := r4.Root().ProvePath([]byte("foo")); ok {
+		t.Fatalf("unexpected proof")
+	}
+}
+
+func TestProvePath(t *testing.T) {
+	r := New[int](WithLeafHash(testLeafHash))
+	keys := []string{"foo", "foobar", "foo/bar", "foo/baz", "zip", "zap", ""}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	root := r.RootHash()
+
+	for i, k := range keys {
+		proof, ok := r.Root().ProvePath([]byte(k))
+		if !ok {
+			t.Fatalf("no proof for %q", k)
+		}
+		leafHash := testLeafHash([]byte(k), i)
+		if !proof.Verify(root, []byte(k), leafHash) {
+			t.Fatalf("proof for %q did not verify", k)
+		}
+		if proof.Verify(root, []byte(k), testLeafHash([]byte(k), i+1)) {
+			t.Fatalf("proof for %q verified with the wrong value", k)
+		}
+		if proof.Verify(root, []byte(k+"x"), leafHash) {
+			t.Fatalf("proof for %q verified with the wrong key", k)
+		}
+	}
+
+	for _, k := range []string{"fo", "foo/", "foo/bax", "zoo"} {
+		if _, ok := r.Root().ProvePath([]byte(k)); ok {
+			t.Fatalf("unexpected proof for %q", k)
+		}
+	}
+
+	// A proof doesn't verify against a different version of the tree.
+	proof, _ := r.Root().ProvePath([]byte("zip"))
+	r2, _, _ := r.Insert([]byte("zoo"), 9)
+	if proof.Verify(r2.RootHash(), []byte("zip"), testLeafHash([]byte("zip"), 4)) {
+		t.Fatalf("stale proof verified")
+	}
+}
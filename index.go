@@ -0,0 +1,75 @@
+package iradix
+
+// indexInsert updates the watermarks and the secondary indexes of the
+// transaction, if any, for a key about to be inserted.
+func (t *Txn[T]) indexInsert(k []byte, v T) {
+	t.marks.update(k)
+	if t.lastMod != nil {
+		t.touch(k)
+	}
+	if t.order != nil {
+		t.orderInsert(k)
+	}
+	if t.suffix != nil {
+		t.suffix.Insert(reverseKey(k), struct{}{})
+	}
+	if t.ngram != nil {
+		t.ngramUpdate(k, true)
+	}
+	if t.priority != nil {
+		t.priorityInsert(k, v)
+	}
+}
+
+// indexDelete updates the watermarks and the secondary indexes of the
+// transaction, if any, for a deleted leaf.
+func (t *Txn[T]) indexDelete(leaf *leafNode[T]) {
+	t.marks.update(leaf.key)
+	if t.lastMod != nil {
+		t.touch(leaf.key)
+	}
+	if t.order != nil {
+		t.order.Delete(seqKey(leaf.getSeq()))
+	}
+	if t.suffix != nil {
+		t.suffix.Delete(reverseKey(leaf.key))
+	}
+	if t.ngram != nil {
+		t.ngramUpdate(leaf.key, false)
+	}
+	if t.priority != nil {
+		t.priority.Delete(t.priorityKey(leaf.key, leaf.val))
+	}
+	if t.meta != nil {
+		t.meta.Delete(leaf.key)
+	}
+}
+
+// indexDeletePrefix updates the watermarks and the secondary indexes of the
+// transaction, if any, for the keys under a prefix about to be deleted.
+func (t *Txn[T]) indexDeletePrefix(prefix []byte) {
+	n := t.root.prefixNode(prefix)
+	if n == nil {
+		return
+	}
+	if minKey, _, ok := n.Minimum(); ok {
+		t.marks.update(minKey)
+	}
+	if maxKey, _, ok := n.Maximum(); ok {
+		t.marks.update(maxKey)
+	}
+	if t.lastMod != nil {
+		t.touchPrefix(prefix)
+	}
+	if t.meta != nil {
+		t.meta.DeletePrefix(prefix)
+	}
+	if t.order == nil && t.suffix == nil && t.ngram == nil && t.priority == nil {
+		return
+	}
+	recursiveWalkNodes(n, func(n *Node[T]) {
+		if n.leaf != nil {
+			t.indexDelete(n.leaf)
+		}
+	})
+}
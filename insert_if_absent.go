@@ -0,0 +1,24 @@
+package iradix
+
+// InsertIfAbsent is used to add a key only if it isn't in the tree yet. It
+// returns the existing value and true if the key is present, in which case
+// nothing is copied, and otherwise the inserted value and false. Inserts
+// rejected like Insert's aren't applied, see TryInsertIfAbsent.
+func (t *Txn[T]) InsertIfAbsent(k []byte, v T) (T, bool) {
+	v, ok, _ := t.TryInsertIfAbsent(k, v)
+	return v, ok
+}
+
+// TryInsertIfAbsent is like InsertIfAbsent, but returns the error that
+// TryInsert would return for a missing key, in which case the zero value is
+// returned.
+func (t *Txn[T]) TryInsertIfAbsent(k []byte, v T) (T, bool, error) {
+	if old, ok := t.Get(k); ok {
+		return old, true, nil
+	}
+	if _, _, err := t.TryInsert(k, v); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return v, false, nil
+}
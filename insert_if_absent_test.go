@@ -0,0 +1,34 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInsertIfAbsent(t *testing.T) {
+	r := New[int](WithMaxTxnMutations[int](1))
+	r, _, _ = r.Insert([]byte("a"), 1)
+
+	txn := r.Txn(false)
+	txn.TrackMutate(true)
+	if v, ok := txn.InsertIfAbsent([]byte("a"), 10); !ok || v != 1 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if txn.Changed() || len(txn.trackChannels) != 0 {
+		t.Fatalf("existing key should not be written")
+	}
+	if v, ok := txn.InsertIfAbsent([]byte("b"), 2); ok || v != 2 {
+		t.Fatalf("bad: %d %v", v, ok)
+	}
+	if v, _ := txn.Get([]byte("b")); v != 2 {
+		t.Fatalf("bad: %d", v)
+	}
+
+	// The transaction is full now.
+	if v, ok, err := txn.TryInsertIfAbsent([]byte("c"), 3); !errors.Is(err, ErrMaxTxnMutations) || ok || v != 0 {
+		t.Fatalf("bad: %d %v %v", v, ok, err)
+	}
+	if v, ok, err := txn.TryInsertIfAbsent([]byte("b"), 3); err != nil || !ok || v != 2 {
+		t.Fatalf("bad: %d %v %v", v, ok, err)
+	}
+}
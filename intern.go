@@ -0,0 +1,73 @@
+package iradix
+
+import "sync"
+
+// Interner deduplicates values, so that equal values inserted under many keys
+// share a single stored instance. This only saves memory for values that
+// refer to their data, such as pointers, strings and slices. An Interner is
+// safe for concurrent use and can be shared by several trees.
+//
+// The interner keeps every distinct value it has seen, including the ones no
+// longer stored in any tree, so it suits sets of values that don't grow
+// without bound.
+type Interner[T any] struct {
+	hash  func(T) uint64
+	equal func(a, b T) bool
+
+	l      sync.Mutex
+	values map[uint64][]T
+	stats  InternerStats
+}
+
+// InternerStats are the statistics of an Interner.
+type InternerStats struct {
+	// Distinct is the number of distinct values held.
+	Distinct int
+
+	// Hits is the number of values that were replaced by an equal instance
+	// already held, which is the number of instances saved.
+	Hits uint64
+}
+
+// NewInterner returns an Interner using the given hash and equality functions.
+// Equal values must have the same hash.
+func NewInterner[T any](hash func(T) uint64, equal func(a, b T) bool) *Interner[T] {
+	return &Interner[T]{
+		hash:   hash,
+		equal:  equal,
+		values: make(map[uint64][]T),
+	}
+}
+
+// WithInterner makes the tree store the values it is given through the
+// interner, see Interner.
+func WithInterner[T any](in *Interner[T]) Option[T] {
+	return func(c *config[T]) {
+		c.interner = in
+	}
+}
+
+// Intern returns the instance held for a value equal to v, holding v itself
+// if there is none.
+func (in *Interner[T]) Intern(v T) T {
+	h := in.hash(v)
+
+	in.l.Lock()
+	defer in.l.Unlock()
+	for _, held := range in.values[h] {
+		if in.equal(held, v) {
+			in.stats.Hits++
+			return held
+		}
+	}
+	in.values[h] = append(in.values[h], v)
+	in.stats.Distinct++
+	return v
+}
+
+// Stats returns the current statistics of the interner.
+func (in *Interner[T]) Stats() InternerStats {
+	in.l.Lock()
+	defer in.l.Unlock()
+	return in.stats
+}
@@ -0,0 +1,42 @@
+package iradix
+
+import (
+	"hash/fnv"
+	"testing"
+)
+
+type internConfig struct {
+	Region string
+	Limit  int
+}
+
+func TestInterner(t *testing.T) {
+	in := NewInterner(func(c *internConfig) uint64 {
+		h := fnv.New64a()
+		h.Write([]byte(c.Region))
+		return h.Sum64() ^ uint64(c.Limit)
+	}, func(a, b *internConfig) bool {
+		return *a == *b
+	})
+	r := New[*internConfig](WithInterner(in))
+
+	txn := r.Txn(false)
+	for _, k := range []string{"svc/a", "svc/b", "svc/c"} {
+		txn.Insert([]byte(k), &internConfig{Region: "eu", Limit: 10})
+	}
+	txn.Insert([]byte("svc/d"), &internConfig{Region: "us", Limit: 10})
+	r = txn.Commit()
+
+	a, _ := r.Get([]byte("svc/a"))
+	c, _ := r.Get([]byte("svc/c"))
+	d, _ := r.Get([]byte("svc/d"))
+	if a != c {
+		t.Fatalf("equal values should share an instance")
+	}
+	if a == d {
+		t.Fatalf("different values should not be merged")
+	}
+	if stats := in.Stats(); stats.Distinct != 2 || stats.Hits != 2 {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+}
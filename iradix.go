@@ -2,7 +2,10 @@ package iradix
 
 import (
 	"bytes"
+	"context"
 	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -24,13 +27,69 @@ const (
 type Tree[T any] struct {
 	root *Node[T]
 	size int
+
+	// conf holds the optional behaviour the tree was created with. It is
+	// shared by every tree derived from this one and must not be modified.
+	conf *config[T]
+
+	// order is the insertion order index, see WithInsertionOrder.
+	order *orderIndex
+
+	// suffix is the suffix index, see WithSuffixIndex.
+	suffix *Tree[struct{}]
+
+	// priority is the priority index, see WithPriority.
+	priority *Tree[struct{}]
+
+	// ngram is the n-gram index, see WithNGramIndex.
+	ngram *Tree[struct{}]
+
+	// meta holds the metadata of the keys, see Txn.SetMeta. It is nil if
+	// none was ever set.
+	meta *Tree[any]
+
+	// sealed holds the sealed prefixes, see Txn.SealPrefix. It is nil if
+	// none were ever sealed.
+	sealed *Tree[struct{}]
+
+	// marks are the keyspace watermarks, see Watermarks.
+	marks watermarks
+
+	// lastMod holds the last modification of each tracked prefix, see
+	// WithLastModified.
+	lastMod *Tree[Modified]
+
+	// cache is the lookup cache, created on first use, see
+	// WithLookupCache.
+	cache atomic.Pointer[lookupCache[T]]
+
+	// recordID identifies the commit that produced the tree in the log of
+	// its recorder, or is zero for none, see WithRecorder.
+	recordID uint64
 }
 
-// New returns an empty Tree
-func New[T any]() *Tree[T] {
+// New returns an empty Tree, configured with any given options
+func New[T any](opts ...Option[T]) *Tree[T] {
 	t := &Tree[T]{
 		root: &Node[T]{},
+		conf: newConfig(opts),
+	}
+	if t.conf.insertionOrder {
+		t.order = &orderIndex{tree: New[[]byte]()}
+	}
+	if t.conf.suffixIndex {
+		t.suffix = New[struct{}]()
+	}
+	if t.conf.priority != nil {
+		t.priority = New[struct{}]()
+	}
+	if t.conf.ngramSize > 0 {
+		t.ngram = New[struct{}]()
+	}
+	if t.conf.lastModDepth > 0 {
+		t.lastMod = New[Modified]()
 	}
+	t.root.settle(t.conf)
 	return t
 }
 
@@ -38,9 +97,40 @@ func (t *Tree[T]) Clone() *Tree[T] {
 	nt := &Tree[T]{}
 	nt.root = t.root.clone(true)
 	nt.size = t.size
+	nt.conf = t.conf
+	nt.order = t.order
+	nt.suffix = t.suffix
+	nt.priority = t.priority
+	nt.ngram = t.ngram
+	nt.meta = t.meta
+	nt.sealed = t.sealed
+	nt.marks = t.marks
+	nt.lastMod = t.lastMod
+	nt.recordID = t.recordID
 	return nt
 }
 
+// DeepClone returns a completely independent copy of the tree. Unlike Clone,
+// the values are copied too using valueClone, so it is safe to use with
+// pointer values that will be mutated. The copy also has its own watch
+// channels, so changes to it never notify watchers of the original tree.
+func (t *Tree[T]) DeepClone(valueClone func(T) T) *Tree[T] {
+	return &Tree[T]{
+		root:     t.root.deepClone(valueClone),
+		size:     t.size,
+		conf:     t.conf,
+		order:    t.order,
+		suffix:   t.suffix,
+		priority: t.priority,
+		ngram:    t.ngram,
+		meta:     t.meta,
+		sealed:   t.sealed,
+		marks:    t.marks,
+		lastMod:  t.lastMod,
+		recordID: t.recordID,
+	}
+}
+
 // Len is used to return the number of elements in the tree
 func (t *Tree[T]) Len() int {
 	return t.size
@@ -77,6 +167,86 @@ type Txn[T any] struct {
 	trackChannels map[chan struct{}]struct{}
 	trackOverflow bool
 	trackMutate   bool
+
+	// trackPolicy limits the number of channels tracked, and trackDegraded
+	// is set once the limit was reached, see SetTrackPolicy.
+	trackPolicy   TrackPolicy
+	trackDegraded bool
+
+	// trackGranularity and trackDepth select which of the modified nodes
+	// are notified, see SetNotifyGranularity. depth is the depth of the
+	// node currently being modified, which is used to apply them.
+	trackGranularity NotifyGranularity
+	trackDepth       int
+	depth            int
+
+	// conf is the configuration of the tree this transaction was started
+	// from, and is carried over to the committed tree.
+	conf *config[T]
+
+	// ctx is checked by the bulk operations, see TxnCtx. It is nil for
+	// transactions started with Txn.
+	ctx context.Context
+
+	// progress collects the statistics reported during bulk inserts, see
+	// SetBulkProgress. It is nil unless requested.
+	progress *bulkProgress
+
+	// err is the first error of a rejected mutation, see Err.
+	err error
+
+	// mutations is the number of mutations made in this transaction, which
+	// is only counted for trees created with WithMaxTxnMutations.
+	mutations int
+
+	// orig is the tree the transaction was started from and changed is set
+	// once a mutation takes effect, see CommitChanged.
+	orig    *Tree[T]
+	changed bool
+
+	// order and orderSeq are the insertion order index being modified and
+	// its last sequence number, see WithInsertionOrder.
+	order    *Txn[[]byte]
+	orderSeq uint64
+
+	// suffix is the suffix index being modified, see WithSuffixIndex.
+	suffix *Txn[struct{}]
+
+	// priority is the priority index being modified, see WithPriority.
+	priority *Txn[struct{}]
+
+	// ngram is the n-gram index being modified, see WithNGramIndex.
+	ngram *Txn[struct{}]
+
+	// meta holds the metadata of the keys being modified, see SetMeta.
+	meta *Txn[any]
+
+	// sealed holds the sealed prefixes, see SealPrefix.
+	sealed *Tree[struct{}]
+
+	// marks are the keyspace watermarks, see Tree.Watermarks.
+	marks watermarks
+
+	// lastMod holds the last modification of each tracked prefix as of the
+	// start of the transaction, and touched the prefixes it modified, see
+	// WithLastModified.
+	lastMod *Tree[Modified]
+	touched map[string]struct{}
+
+	// lapStart is when the current phase of the transaction started and
+	// profile holds the durations of the phases so far, see
+	// WithCommitProfiler.
+	lapStart time.Time
+	profile  CommitProfile
+
+	// recorded holds the mutations recorded since the last commit and
+	// recordParent identifies the tree they apply to, see WithRecorder.
+	recorded     []byte
+	recordParent uint64
+
+	// repairs are the repairs made by ApplyRepairs, which are taken off
+	// the queue once the transaction commits, see WithReadValidator.
+	repairs appliedRepairs
 }
 
 // Txn starts a new transaction that can be used to mutate the tree
@@ -84,9 +254,35 @@ func (t *Tree[T]) Txn(clone bool) *Txn[T] {
 	t.root.lazyRefCount++
 	t.root.processLazyRefCount()
 	txn := &Txn[T]{
-		root: t.root.clone(clone),
-		snap: t.root,
-		size: t.size,
+		root:    t.root.clone(clone),
+		snap:    t.root,
+		orig:    t,
+		size:    t.size,
+		conf:    t.conf,
+		sealed:  t.sealed,
+		marks:   t.marks,
+		lastMod: t.lastMod,
+
+		recordParent: t.recordID,
+	}
+	if t.order != nil {
+		txn.order = t.order.tree.Txn(false)
+		txn.orderSeq = t.order.seq
+	}
+	if t.suffix != nil {
+		txn.suffix = t.suffix.Txn(false)
+	}
+	if t.priority != nil {
+		txn.priority = t.priority.Txn(false)
+	}
+	if t.ngram != nil {
+		txn.ngram = t.ngram.Txn(false)
+	}
+	if t.meta != nil {
+		txn.meta = t.meta.Txn(false)
+	}
+	if t.conf.profiling() {
+		txn.lapStart = time.Now()
 	}
 	return txn
 }
@@ -100,6 +296,45 @@ func (t *Txn[T]) Clone() *Txn[T] {
 		root: t.root.clone(true),
 		snap: t.snap,
 		size: t.size,
+		conf: t.conf,
+		ctx:  t.ctx,
+		err:  t.err,
+
+		sealed:  t.sealed,
+		marks:   t.marks,
+		lastMod: t.lastMod,
+
+		mutations: t.mutations,
+		orig:      t.orig,
+		changed:   t.changed,
+		lapStart:  t.lapStart,
+		orderSeq:  t.orderSeq,
+
+		recorded:     append([]byte(nil), t.recorded...),
+		recordParent: t.recordParent,
+
+		repairs: t.repairs.clone(),
+	}
+	if t.order != nil {
+		txn.order = t.order.Clone()
+	}
+	if t.suffix != nil {
+		txn.suffix = t.suffix.Clone()
+	}
+	if t.priority != nil {
+		txn.priority = t.priority.Clone()
+	}
+	if t.ngram != nil {
+		txn.ngram = t.ngram.Clone()
+	}
+	if t.meta != nil {
+		txn.meta = t.meta.Clone()
+	}
+	if t.touched != nil {
+		txn.touched = make(map[string]struct{}, len(t.touched))
+		for p := range t.touched {
+			txn.touched[p] = struct{}{}
+		}
 	}
 	return txn
 }
@@ -111,11 +346,65 @@ func (t *Txn[T]) TrackMutate(track bool) {
 	t.trackMutate = track
 }
 
+// NotifyGranularity selects which watch channels are notified when a
+// transaction with mutation tracking is committed.
+type NotifyGranularity int
+
+const (
+	// NotifyLeaf notifies every modified node and leaf. This is the
+	// default and the most precise.
+	NotifyLeaf NotifyGranularity = iota
+
+	// NotifySubtree only notifies the modified nodes down to a given depth,
+	// and no leaves. Watchers of deeper nodes and of leaves are not
+	// notified at all, so they should watch an ancestor instead.
+	NotifySubtree
+
+	// NotifyRoot only notifies the root node.
+	NotifyRoot
+)
+
+// SetNotifyGranularity selects which channels are notified for mutations when
+// TrackMutate is enabled, trading precision for commit speed on very large
+// transactions. For NotifySubtree, nodes up to depth levels below the root
+// are notified, the root being at depth zero. The granularity should be set
+// before any mutation.
+func (t *Txn[T]) SetNotifyGranularity(g NotifyGranularity, depth int) {
+	t.trackGranularity = g
+	t.trackDepth = depth
+}
+
+// tracksNodeAt returns true if a modified node at the given depth should be
+// notified.
+func (t *Txn[T]) tracksNodeAt(depth int) bool {
+	if !t.trackMutate || t.trackStopped() {
+		return false
+	}
+	switch t.trackGranularity {
+	case NotifySubtree:
+		return depth <= t.trackDepth
+	case NotifyRoot:
+		return depth == 0
+	default:
+		return true
+	}
+}
+
+// tracksLeaves returns true if modified leaves should be notified.
+func (t *Txn[T]) tracksLeaves() bool {
+	return t.trackMutate && !t.trackStopped() && t.trackGranularity == NotifyLeaf
+}
+
 // trackChannel safely attempts to track the given mutation channel, setting the
 // overflow flag if we can no longer track any more. This limits the amount of
 // state that will accumulate during a transaction and we have a slower algorithm
 // to switch to if we overflow.
 func (t *Txn[T]) trackChannel(node *Node[T]) {
+	// Nodes without a watch channel of their own have nothing to notify.
+	if node.noWatch && !node.hasMutateCh() {
+		return
+	}
+
 	// In overflow, make sure we don't store any more objects.
 	//if t.trackOverflow {
 	//	return
@@ -142,6 +431,7 @@ func (t *Txn[T]) trackChannel(node *Node[T]) {
 	// Otherwise we are good to track it.
 	t.trackChannels[node.getMutateCh()] = struct{}{}
 	node.setMutateCh(nil)
+	t.checkTrackPolicy()
 }
 
 func (t *Txn[T]) trackChannelLeaf(node *leafNode[T]) {
@@ -154,6 +444,7 @@ func (t *Txn[T]) trackChannelLeaf(node *leafNode[T]) {
 	// Otherwise we are good to track it.
 	t.trackChannels[node.getMutateCh()] = struct{}{}
 	node.setMutateCh(nil)
+	t.checkTrackPolicy()
 }
 
 // writeNode returns a node to be modified, if the current node has already been
@@ -178,23 +469,27 @@ func (t *Txn[T]) writeNode(n *Node[T], forLeafUpdate bool) *Node[T] {
 	// update we track it, in case the initial write to this node didn't
 	// update the leaf.
 	if _, ok := t.writable.Get(n); ok {
-		if t.trackMutate && forLeafUpdate && n.leaf != nil {
+		if t.tracksLeaves() && forLeafUpdate && n.tracksLeaf() {
 			t.trackChannelLeaf(n.leaf)
 		}
 		return n
 	}
 
 	// Mark this node as being mutated.
-	if t.trackMutate {
+	if t.tracksNodeAt(t.depth) {
 		t.trackChannel(n)
 	}
 
 	// Mark its leaf as being mutated, if appropriate.
-	if t.trackMutate && forLeafUpdate && n.leaf != nil {
+	if t.tracksLeaves() && forLeafUpdate && n.tracksLeaf() {
 		t.trackChannelLeaf(n.leaf)
 	}
 
 	if n.refCount <= 1 {
+		// The node is modified in place, so anything derived from its
+		// contents has to be computed again on commit.
+		n.auditCheckWrite()
+		n.unsettle()
 		return n
 	}
 
@@ -221,27 +516,28 @@ func (t *Txn[T]) writeNode(n *Node[T], forLeafUpdate bool) *Node[T] {
 	return nc
 }
 
-// Visit all the nodes in the tree under n, and add their mutateChannels to the transaction
+// Visit all the nodes in the tree under n, which is at the given depth, and add
+// their mutateChannels to the transaction
 // Returns the size of the subtree visited
-func (t *Txn[T]) trackChannelsAndCount(n *Node[T]) int {
+func (t *Txn[T]) trackChannelsAndCount(n *Node[T], depth int) int {
 	// Count only leaf nodes
 	leaves := 0
 	if n.leaf != nil {
 		leaves = 1
 	}
 	// Mark this node as being mutated.
-	if t.trackMutate {
+	if t.tracksNodeAt(depth) {
 		t.trackChannel(n)
 	}
 
 	// Mark its leaf as being mutated, if appropriate.
-	if t.trackMutate && n.leaf != nil {
+	if t.tracksLeaves() && n.tracksLeaf() {
 		t.trackChannelLeaf(n.leaf)
 	}
 
 	// Recurse on the children
 	for _, e := range n.edges {
-		leaves += t.trackChannelsAndCount(e.node)
+		leaves += t.trackChannelsAndCount(e.node, depth+1)
 	}
 	return leaves
 }
@@ -256,7 +552,7 @@ func (t *Txn[T]) mergeChild(n *Node[T]) {
 	e := n.edges[0]
 	child := e.node
 	child.processLazyRefCount()
-	if t.trackMutate {
+	if t.tracksNodeAt(t.depth + 1) {
 		t.trackChannel(child)
 	}
 
@@ -287,11 +583,8 @@ func (t *Txn[T]) insert(n *Node[T], k, search []byte, v T) (*Node[T], T, bool) {
 		}
 
 		nc := t.writeNode(n, true)
-		nc.leaf = &leafNode[T]{
-			key:      k,
-			val:      v,
-			refCount: 1,
-		}
+		nc.leaf = t.newLeaf(k, v)
+		t.progress.created(0, t.depth)
 		return nc, oldVal, didUpdate
 	}
 
@@ -303,17 +596,14 @@ func (t *Txn[T]) insert(n *Node[T], k, search []byte, v T) (*Node[T], T, bool) {
 		e := edge[T]{
 			label: search[0],
 			node: &Node[T]{
-				leaf: &leafNode[T]{
-					key:      k,
-					val:      v,
-					refCount: 1,
-				},
+				leaf:     t.newLeaf(k, v),
 				refCount: 1,
 				prefix:   search,
 			},
 		}
 		nc := t.writeNode(n, false)
 		nc.addEdge(e)
+		t.progress.created(1, t.depth+1)
 		return nc, zero, false
 	}
 
@@ -321,7 +611,9 @@ func (t *Txn[T]) insert(n *Node[T], k, search []byte, v T) (*Node[T], T, bool) {
 	commonPrefix := longestPrefix(search, child.prefix)
 	if commonPrefix == len(child.prefix) {
 		search = search[commonPrefix:]
+		t.depth++
 		newChild, oldVal, didUpdate := t.insert(child, k, search, v)
+		t.depth--
 		if newChild != nil {
 			nc := t.writeNode(n, false)
 			nc.edges[idx].node = newChild
@@ -342,7 +634,9 @@ func (t *Txn[T]) insert(n *Node[T], k, search []byte, v T) (*Node[T], T, bool) {
 	})
 
 	// Restore the existing child node
+	t.depth++
 	modChild := t.writeNode(child, false)
+	t.depth--
 	splitNode.addEdge(edge[T]{
 		label: modChild.prefix[commonPrefix],
 		node:  modChild,
@@ -350,18 +644,16 @@ func (t *Txn[T]) insert(n *Node[T], k, search []byte, v T) (*Node[T], T, bool) {
 	modChild.prefix = modChild.prefix[commonPrefix:]
 
 	// Create a new leaf node
-	leaf := &leafNode[T]{
-		key:      k,
-		val:      v,
-		refCount: 1,
-	}
+	leaf := t.newLeaf(k, v)
 
 	// If the new key is a subset, add to to this node
 	search = search[commonPrefix:]
 	if len(search) == 0 {
 		splitNode.leaf = leaf
+		t.progress.created(1, t.depth+2)
 		return nc, zero, false
 	}
+	t.progress.created(2, t.depth+2)
 
 	// Create a new edge for the node
 	splitNode.addEdge(edge[T]{
@@ -409,7 +701,9 @@ func (t *Txn[T]) delete(n *Node[T], search []byte) (*Node[T], *leafNode[T]) {
 
 	// Consume the search prefix
 	search = search[len(child.prefix):]
+	t.depth++
 	newChild, leaf := t.delete(child, search)
+	t.depth--
 	if newChild == nil {
 		return nil, nil
 	}
@@ -434,10 +728,11 @@ func (t *Txn[T]) delete(n *Node[T], search []byte) (*Node[T], *leafNode[T]) {
 
 // delete does a recursive deletion
 func (t *Txn[T]) deletePrefix(n *Node[T], search []byte) (*Node[T], int) {
+	n.processLazyRefCount()
 	// Check for key exhaustion
 	if len(search) == 0 {
 		nc := t.writeNode(n, true)
-		numDel := t.trackChannelsAndCount(n)
+		numDel := t.trackChannelsAndCount(n, t.depth)
 		if n.isLeaf() {
 			nc.leaf = nil
 		}
@@ -460,7 +755,9 @@ func (t *Txn[T]) deletePrefix(n *Node[T], search []byte) (*Node[T], int) {
 	} else {
 		search = search[len(child.prefix):]
 	}
+	t.depth++
 	newChild, numDeletions := t.deletePrefix(child, search)
+	t.depth--
 	if newChild == nil {
 		return nil, 0
 	}
@@ -484,8 +781,37 @@ func (t *Txn[T]) deletePrefix(n *Node[T], search []byte) (*Node[T], int) {
 }
 
 // Insert is used to add or update a given key. The return provides
-// the previous value and a bool indicating if any was set.
+// the previous value and a bool indicating if any was set. Keys rejected by
+// the tree's key validator, keys under a sealed prefix and inserts beyond the
+// transaction's mutation limit are not applied, see TryInsert. Such an insert
+// returns zero values and is lost without any other signal than Err, and
+// Commit publishes the transaction without it; use TryCommit to refuse to.
 func (t *Txn[T]) Insert(k []byte, v T) (T, bool) {
+	oldVal, didUpdate, _ := t.TryInsert(k, v)
+	return oldVal, didUpdate
+}
+
+// TryInsert is like Insert, but reports why an insert was not applied. It
+// returns:
+//
+//   - an *InvalidKeyError if the key is rejected by the tree's key
+//     validator, see WithKeyValidator;
+//   - ErrSealed if the key is under a sealed prefix, see SealPrefix;
+//   - ErrMaxTxnMutations if the transaction already applied the maximum
+//     number of mutations, see WithMaxTxnMutations.
+//
+// The checks are made in that order and the transaction is left unchanged
+// when any of them fails. The first error of the transaction is also kept
+// as its sticky error, returned by Err. It doesn't block later calls, which
+// are checked on their own, but it makes TryCommit refuse to commit, while
+// Commit and CommitOnly publish the applied mutations without the rejected
+// ones.
+func (t *Txn[T]) TryInsert(k []byte, v T) (T, bool, error) {
+	v, err := t.admitInsert(k, v)
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
 	newRoot, oldVal, didUpdate := t.insert(t.root, k, k, v)
 	if newRoot != nil {
 		t.root = newRoot
@@ -493,35 +819,138 @@ func (t *Txn[T]) Insert(k []byte, v T) (T, bool) {
 	if !didUpdate {
 		t.size++
 	}
-	return oldVal, didUpdate
+	t.changed = true
+	return oldVal, didUpdate, nil
+}
+
+// admitInsert checks that an insert may be applied, and updates the indexes
+// and the recorder for it before the tree itself is modified. It returns the
+// value to store, which may have been interned.
+func (t *Txn[T]) admitInsert(k []byte, v T) (T, error) {
+	if err := t.conf.validateKey(k); err != nil {
+		return v, t.reject(err)
+	}
+	if err := t.checkSealed(k); err != nil {
+		return v, err
+	}
+	if err := t.countMutation(); err != nil {
+		return v, err
+	}
+	t.indexInsert(k, v)
+	if t.conf != nil && t.conf.interner != nil {
+		v = t.conf.interner.Intern(v)
+	}
+	t.record(recordInsert, k, v)
+	return v, nil
 }
 
 // Delete is used to delete a given key. Returns the old value if any,
-// and a bool indicating if the key was set.
+// and a bool indicating if the key was set. Deletes under a sealed prefix or
+// beyond the transaction's mutation limit are not applied, see TryDelete, and
+// are lost like rejected inserts, see Insert.
 func (t *Txn[T]) Delete(k []byte) (T, bool) {
+	oldVal, didDelete, _ := t.TryDelete(k)
+	return oldVal, didDelete
+}
+
+// TryDelete is like Delete, but returns ErrSealed if the key is under a
+// sealed prefix, see SealPrefix, or ErrMaxTxnMutations if the transaction is
+// full, see WithMaxTxnMutations. The error is also recorded for Err.
+func (t *Txn[T]) TryDelete(k []byte) (T, bool, error) {
 	var zero T
+	if err := t.checkSealed(k); err != nil {
+		return zero, false, err
+	}
+	if err := t.countMutation(); err != nil {
+		return zero, false, err
+	}
+	if leaf := t.deleteLeaf(k); leaf != nil {
+		return leaf.val, true, nil
+	}
+	return zero, false, nil
+}
+
+// deleteLeaf deletes k from the transaction, once its checks are done, and
+// returns the leaf it held, or nil if the key is missing.
+func (t *Txn[T]) deleteLeaf(k []byte) *leafNode[T] {
 	newRoot, leaf := t.delete(t.root, k)
 	if newRoot != nil {
 		t.root = newRoot
 	}
 	if leaf != nil {
+		var zero T
+		t.indexDelete(leaf)
+		t.record(recordDelete, k, zero)
 		t.size--
-		return leaf.val, true
+		t.changed = true
 	}
-	return zero, false
+	return leaf
 }
 
 // DeletePrefix is used to delete an entire subtree that matches the prefix
 // This will delete all nodes under that prefix
+// With WithMaxTxnMutations, every key deleted counts as a mutation, and a
+// prefix matching nothing doesn't count. The deletion may take the
+// transaction past its limit, but isn't applied if the transaction is already
+// full. It isn't applied either if it would delete
+// keys under a sealed prefix, see SealPrefix.
 func (t *Txn[T]) DeletePrefix(prefix []byte) bool {
+	_, ok := t.deletePrefixCount(prefix)
+	return ok
+}
+
+// DeletePrefixCount is like DeletePrefix, but returns the number of keys
+// deleted. The whole subtree is dropped at once, copying only the nodes on
+// the path to the prefix, so it is much cheaper than deleting the keys one by
+// one.
+func (t *Txn[T]) DeletePrefixCount(prefix []byte) int {
+	n, _ := t.deletePrefixCount(prefix)
+	return n
+}
+
+// deletePrefixCount deletes the subtree under the prefix, returning the
+// number of keys deleted and false if it wasn't applied, see DeletePrefix.
+func (t *Txn[T]) deletePrefixCount(prefix []byte) (int, bool) {
+	if err := t.checkSealedPrefix(prefix); err != nil {
+		return 0, false
+	}
+	if err := t.checkMutationLimit(); err != nil {
+		return 0, false
+	}
+	t.indexDeletePrefix(prefix)
 	newRoot, numDeletions := t.deletePrefix(t.root, prefix)
 	if newRoot != nil {
 		t.root = newRoot
 		t.size = t.size - numDeletions
-		return true
+		t.addMutations(numDeletions)
+		t.changed = t.changed || numDeletions > 0
+		var zero T
+		t.record(recordDeletePrefix, prefix, zero)
+		return numDeletions, true
 	}
-	return false
+	return 0, false
+}
+
+// Len returns the number of elements in the tree as modified by this
+// transaction, including any changes that have not been committed yet.
+func (t *Txn[T]) Len() int {
+	return t.size
+}
+
+// Err returns the first error of a mutation rejected in this transaction,
+// such as an *InvalidKeyError, ErrSealed or ErrMaxTxnMutations, or nil if
+// there was none. Rejected mutations are not applied. They don't prevent the
+// others from being committed by Commit, but TryCommit refuses to commit.
+func (t *Txn[T]) Err() error {
+	return t.err
+}
 
+// reject records the error of a rejected mutation for Err, and returns it.
+func (t *Txn[T]) reject(err error) error {
+	if t.err == nil {
+		t.err = err
+	}
+	return err
 }
 
 // Root returns the current root of the radix tree within this
@@ -544,22 +973,77 @@ func (t *Txn[T]) GetWatch(k []byte) (<-chan struct{}, T, bool) {
 }
 
 // Commit is used to finalize the transaction and return a new tree. If mutation
-// tracking is turned on then notifications will also be issued.
+// tracking is turned on then notifications will also be issued. Mutations
+// rejected earlier, see Err, are left out without any error, so a partially
+// applied transaction is published; TryCommit checks for them instead.
 func (t *Txn[T]) Commit() *Tree[T] {
-	nt := t.CommitOnly()
+	nt := t.commitOnly()
 	if t.trackMutate {
+		if t.conf.profiling() {
+			t.profile.Channels = len(t.trackChannels)
+		}
 		t.Notify()
 	}
+	t.lap(&t.profile.Notify)
+	t.reportProfile()
 	return nt
 }
 
+// TryCommit is like Commit, but if a mutation of the transaction was rejected
+// it returns the error, see Err, instead of publishing the transaction without
+// it. Nothing is committed or notified then, and the transaction can only be
+// discarded.
+func (t *Txn[T]) TryCommit() (*Tree[T], error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return t.Commit(), nil
+}
+
 // CommitOnly is used to finalize the transaction and return a new tree, but
 // does not issue any notifications until Notify is called.
 func (t *Txn[T]) CommitOnly() *Tree[T] {
-	t.root.lazyRefCount--
+	nt := t.commitOnly()
+	t.reportProfile()
+	return nt
+}
+
+// commitOnly finalizes the transaction for Commit and CommitOnly.
+func (t *Txn[T]) commitOnly() *Tree[T] {
+	t.profile = CommitProfile{}
+	t.lap(&t.profile.Mutation)
+
+	// The tree the transaction was started from is still a valid tree that
+	// may be read, so the references taken by Txn are kept. Releasing them
+	// here would let the next transaction modify its nodes in place.
 	t.root.processLazyRefCount()
-	nt := &Tree[T]{t.root.clone(false), t.size}
+	t.lap(&t.profile.Refcount)
+	t.root.settle(t.conf)
+	t.root.auditSeal()
+	t.lap(&t.profile.Settle)
+	nt := &Tree[T]{root: t.root.clone(false), size: t.size, conf: t.conf, sealed: t.sealed, marks: t.marks}
+	nt.recordID = t.flushRecorded()
+	t.flushRepairs()
+	if t.order != nil {
+		nt.order = &orderIndex{tree: t.order.CommitOnly(), seq: t.orderSeq}
+	}
+	if t.suffix != nil {
+		nt.suffix = t.suffix.CommitOnly()
+	}
+	if t.priority != nil {
+		nt.priority = t.priority.CommitOnly()
+	}
+	if t.ngram != nil {
+		nt.ngram = t.ngram.CommitOnly()
+	}
+	if t.meta != nil {
+		nt.meta = t.meta.CommitOnly()
+	}
+	if t.lastMod != nil {
+		nt.lastMod = t.commitLastModified(nt.root.generation)
+	}
 	t.writable = nil
+	t.lap(&t.profile.Publish)
 	return nt
 }
 
@@ -659,6 +1143,20 @@ func (t *Tree[T]) Insert(k []byte, v T) (*Tree[T], T, bool) {
 	return txn.Commit(), old, ok
 }
 
+// TryInsert is like Insert, but returns an *InvalidKeyError if the key is
+// rejected by the tree's key validator, or ErrSealed if the key is under a
+// sealed prefix. In both cases the tree is returned unchanged. The insert
+// runs in a transaction of its own, so it never hits the limit set by
+// WithMaxTxnMutations and there is no sticky error to carry over.
+func (t *Tree[T]) TryInsert(k []byte, v T) (*Tree[T], T, bool, error) {
+	txn := t.Txn(false)
+	old, ok, err := txn.TryInsert(k, v)
+	if err != nil {
+		return t, old, ok, err
+	}
+	return txn.Commit(), old, ok, nil
+}
+
 // Delete is used to delete a given key. Returns the new tree,
 // old value if any, and a bool indicating if the key was set.
 func (t *Tree[T]) Delete(k []byte) (*Tree[T], T, bool) {
@@ -684,7 +1182,30 @@ func (t *Tree[T]) Root() *Node[T] {
 // Get is used to lookup a specific key, returning
 // the value and if it was found
 func (t *Tree[T]) Get(k []byte) (T, bool) {
-	return t.root.Get(k)
+	var v T
+	var ok bool
+	if c := t.lookupCache(); c != nil {
+		if leaf := c.get(t.root, k); leaf != nil {
+			v, ok = leaf.val, true
+		}
+	} else {
+		v, ok = t.root.Get(k)
+	}
+	if ok && t.conf != nil && t.conf.readRepair != nil {
+		return t.conf.readRepair.check(k, v)
+	}
+	return v, ok
+}
+
+// SnapshotWalkPrefix walks the keys under the prefix like Node.WalkPrefix, on
+// the root of the tree captured once at the start of the call. It returns that
+// root, so that any further reads can be made against the same snapshot
+// instead of loading the root again, which could interleave with a
+// concurrent Commit.
+func (t *Tree[T]) SnapshotWalkPrefix(prefix []byte, fn WalkFn[T]) *Node[T] {
+	root := t.root
+	root.WalkPrefix(prefix, fn)
+	return root
 }
 
 // longestPrefix finds the length of the shared prefix
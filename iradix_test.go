@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"testing/quick"
@@ -359,6 +360,33 @@ func TestDeletePrefix(t *testing.T) {
 	}
 }
 
+func TestDeletePrefixCount(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"", "a", "ab", "abc", "abd", "b", "bc"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	txn := r.Txn(false)
+	if n := txn.DeletePrefixCount([]byte("ab")); n != 3 {
+		t.Fatalf("bad: %d", n)
+	}
+	if n := txn.DeletePrefixCount([]byte("ab")); n != 0 {
+		t.Fatalf("bad: %d", n)
+	}
+	if n := txn.DeletePrefixCount([]byte("x")); n != 0 {
+		t.Fatalf("bad: %d", n)
+	}
+	if n := txn.DeletePrefixCount([]byte("b")); n != 2 || txn.Len() != 2 {
+		t.Fatalf("bad: %d %d", n, txn.Len())
+	}
+	if n := txn.DeletePrefixCount(nil); n != 2 || txn.Len() != 0 {
+		t.Fatalf("bad: %d %d", n, txn.Len())
+	}
+	if r.Len() != 7 {
+		t.Fatalf("original tree changed")
+	}
+}
+
 func TestTrackMutate_DeletePrefix(t *testing.T) {
 
 	r := New[any]()
@@ -508,6 +536,53 @@ func TestLongestPrefix(t *testing.T) {
 	}
 }
 
+func TestLongestPrefixWatch(t *testing.T) {
+	r := New[any]()
+	for _, k := range []string{"", "foo", "foo/bar/baz", "zip"} {
+		r, _, _ = r.Insert([]byte(k), k)
+	}
+	apply := func(fn func(txn *Txn[any])) {
+		txn := r.Txn(true)
+		txn.TrackMutate(true)
+		fn(txn)
+		r = txn.Commit()
+	}
+
+	watch, k, v, ok := r.Root().LongestPrefixWatch([]byte("foo/bar/qux"))
+	if !ok || string(k) != "foo" || v != "foo" {
+		t.Fatalf("bad: %q %v %v", k, v, ok)
+	}
+
+	// Unrelated keys and longer keys off the path don't fire.
+	apply(func(txn *Txn[any]) { txn.Insert([]byte("zap"), nil) })
+	if watchFired(watch) {
+		t.Fatalf("should not fire")
+	}
+
+	// A longer prefix of the key does.
+	apply(func(txn *Txn[any]) { txn.Insert([]byte("foo/bar"), nil) })
+	if !watchFired(watch) {
+		t.Fatalf("should fire")
+	}
+
+	// As does deleting the match.
+	watch, k, _, _ = r.Root().LongestPrefixWatch([]byte("foo/bar/qux"))
+	if string(k) != "foo/bar" {
+		t.Fatalf("bad: %q", k)
+	}
+	apply(func(txn *Txn[any]) { txn.Delete([]byte("foo/bar")) })
+	if !watchFired(watch) {
+		t.Fatalf("should fire")
+	}
+
+	// Without a match the root's channel is returned.
+	r2 := New[any]()
+	watch, _, _, ok = r2.Root().LongestPrefixWatch([]byte("foo"))
+	if ok || watch == nil {
+		t.Fatalf("bad")
+	}
+}
+
 func TestWalkPrefix(t *testing.T) {
 	r := New[any]()
 
@@ -588,6 +663,122 @@ func TestWalkPrefix(t *testing.T) {
 	}
 }
 
+func TestMinimumMaximumWatch(t *testing.T) {
+	r := New[any]()
+	for _, k := range []string{"b", "c/1", "c/2"} {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+	minWatch, minKey, _, ok := r.Root().MinimumWatch()
+	if !ok || string(minKey) != "b" {
+		t.Fatalf("bad: %q", minKey)
+	}
+	maxWatch, maxKey, _, ok := r.Root().MaximumWatch()
+	if !ok || string(maxKey) != "c/2" {
+		t.Fatalf("bad: %q", maxKey)
+	}
+
+	txn := r.Txn(true)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("a"), nil)
+	r = txn.Commit()
+	if !watchFired(minWatch) || !watchFired(maxWatch) {
+		t.Fatalf("should fire")
+	}
+	if _, minKey, _, _ = r.Root().MinimumWatch(); string(minKey) != "a" {
+		t.Fatalf("bad: %q", minKey)
+	}
+
+	// A subtree's watch doesn't fire for changes outside of it.
+	sub := r.Root().prefixNode([]byte("c/"))
+	maxWatch, maxKey, _, _ = sub.MaximumWatch()
+	if string(maxKey) != "c/2" {
+		t.Fatalf("bad: %q", maxKey)
+	}
+	txn = r.Txn(true)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("0"), nil)
+	r = txn.Commit()
+	if watchFired(maxWatch) {
+		t.Fatalf("should not fire")
+	}
+
+	_, _, _, ok = New[any]().Root().MinimumWatch()
+	if ok {
+		t.Fatalf("empty tree has no minimum")
+	}
+}
+
+func TestWalkPrefixWatch(t *testing.T) {
+	r := New[any]()
+	keys := []string{"foo/bar/baz", "foo/baz/bar", "foobar", "zipzap"}
+	for _, k := range keys {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+
+	var out []string
+	watch := r.Root().WalkPrefixWatch([]byte("foo/ba"), func(k []byte, v any) bool {
+		out = append(out, string(k))
+		return false
+	})
+	if !slices.Equal(out, []string{"foo/bar/baz", "foo/baz/bar"}) {
+		t.Fatalf("bad: %v", out)
+	}
+	missWatch := r.Root().WalkPrefixWatch([]byte("foo/q"), func(k []byte, v any) bool {
+		t.Fatalf("unexpected key %q", k)
+		return false
+	})
+
+	insert := func(k string) {
+		txn := r.Txn(true)
+		txn.TrackMutate(true)
+		txn.Insert([]byte(k), nil)
+		r = txn.Commit()
+	}
+
+	// A change outside the prefix doesn't fire.
+	insert("zipzip")
+	if watchFired(watch) || watchFired(missWatch) {
+		t.Fatalf("should not fire")
+	}
+
+	// A change under the prefix does.
+	insert("foo/bax")
+	if !watchFired(watch) {
+		t.Fatalf("should fire")
+	}
+
+	// So does a new key under a prefix that had no keys.
+	insert("foo/qux")
+	if !watchFired(missWatch) {
+		t.Fatalf("should fire")
+	}
+}
+
+func TestSnapshotWalkPrefix(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a/1", "a/2", "b"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	var out []string
+	root := r.SnapshotWalkPrefix([]byte("a/"), func(k []byte, v int) bool {
+		out = append(out, string(k))
+		return false
+	})
+	if !slices.Equal(out, []string{"a/1", "a/2"}) {
+		t.Fatalf("bad: %v", out)
+	}
+	if root != r.Root() {
+		t.Fatalf("should return the walked root")
+	}
+
+	// Later reads against the returned root see the same snapshot.
+	r, _, _ = r.Insert([]byte("a/3"), 3)
+	if _, ok := root.Get([]byte("a/3")); ok {
+		t.Fatalf("snapshot should not see later writes")
+	}
+}
+
 func TestWalkPath(t *testing.T) {
 	r := New[any]()
 
@@ -864,6 +1055,15 @@ func isClosed(ch chan struct{}) bool {
 
 // hasAnyClosedMutateCh scans the given tree and returns true if there are any
 // closed mutate channels on any nodes or leaves.
+func watchFired(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
 func hasAnyClosedMutateCh[T any](r *Tree[T]) bool {
 	for iter := r.root.rawIterator(); iter.Front() != nil; iter.Next() {
 		n := iter.Front()
@@ -1527,6 +1727,56 @@ func TestTrackMutate_cachedNodeChange(t *testing.T) {
 	}
 }
 
+func TestTrackMutate_Granularity(t *testing.T) {
+	keys := []string{"foo/bar/baz", "foo/baz/bar", "foobar", "zipzap"}
+	cases := []struct {
+		name        string
+		granularity NotifyGranularity
+		depth       int
+		parent      bool
+		leaf        bool
+	}{
+		{"leaf", NotifyLeaf, 0, true, true},
+		{"subtree", NotifySubtree, 1, true, false},
+		{"subtree root", NotifySubtree, 0, false, false},
+		{"root", NotifyRoot, 0, false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := New[any]()
+			for _, k := range keys {
+				r, _, _ = r.Insert([]byte(k), nil)
+			}
+			rootWatch, _, _ := r.Root().GetWatch(nil)
+			parentWatch, _, _ := r.Root().GetWatch([]byte("foo"))
+			leafWatch, _, _ := r.Root().GetWatch([]byte("foo/bar/baz"))
+			otherWatch, _, _ := r.Root().GetWatch([]byte("zipzap"))
+
+			txn := r.Txn(true)
+			txn.TrackMutate(true)
+			txn.SetNotifyGranularity(tc.granularity, tc.depth)
+			txn.Insert([]byte("foo/bar/baz"), 1)
+			r = txn.Commit()
+			if hasAnyClosedMutateCh(r) {
+				t.Fatalf("bad")
+			}
+
+			if !watchFired(rootWatch) {
+				t.Fatalf("root should be notified")
+			}
+			if watchFired(parentWatch) != tc.parent {
+				t.Fatalf("parent notified: %v, want %v", watchFired(parentWatch), tc.parent)
+			}
+			if watchFired(leafWatch) != tc.leaf {
+				t.Fatalf("leaf notified: %v, want %v", watchFired(leafWatch), tc.leaf)
+			}
+			if watchFired(otherWatch) {
+				t.Fatalf("unrelated leaf should not be notified")
+			}
+		})
+	}
+}
+
 func TestLenTxn(t *testing.T) {
 	r := New[any]()
 
@@ -1542,8 +1792,16 @@ func TestLenTxn(t *testing.T) {
 		"foobar",
 		"nochange",
 	}
-	for _, k := range keys {
+	for i, k := range keys {
 		txn.Insert([]byte(k), nil)
+		if txn.Len() != i+1 {
+			t.Fatalf("bad: expected pending len %d, got %d", i+1, txn.Len())
+		}
+	}
+	// Updating an existing key must not change the size
+	txn.Insert([]byte(keys[0]), nil)
+	if txn.Len() != len(keys) {
+		t.Fatalf("bad: expected pending len %d, got %d", len(keys), txn.Len())
 	}
 	r = txn.Commit()
 
@@ -1552,9 +1810,19 @@ func TestLenTxn(t *testing.T) {
 	}
 
 	txn = r.Txn(true)
+	txn.DeletePrefix([]byte("foo/"))
+	if txn.Len() != 2 {
+		t.Fatalf("bad: expected pending len 2, got %d", txn.Len())
+	}
+	if r.Len() != len(keys) {
+		t.Fatalf("committed tree len changed: %d", r.Len())
+	}
 	for _, k := range keys {
 		txn.Delete([]byte(k))
 	}
+	if txn.Len() != 0 {
+		t.Fatalf("bad: expected pending len 0, got %d", txn.Len())
+	}
 	r = txn.Commit()
 
 	if r.Len() != 0 {
@@ -1812,6 +2080,46 @@ func (s readableString) Generate(rand *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(readableString(b))
 }
 
+func TestIterateRange(t *testing.T) {
+	keys := []string{"", "a", "aa", "ab", "abc", "b", "ba", "c", "foo", "foobar"}
+	r := New[int]()
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	cases := []struct {
+		start, end string
+		nilEnd     bool
+		want       []string
+	}{
+		{"a", "b", false, []string{"a", "aa", "ab", "abc"}},
+		{"aa", "abc", false, []string{"aa", "ab"}},
+		{"", "a", false, []string{""}},
+		{"ab", "ab", false, nil},
+		{"b", "a", false, nil},
+		{"bb", "", true, []string{"c", "foo", "foobar"}},
+		{"c", "foobar", false, []string{"c", "foo"}},
+		{"d", "z", false, []string{"foo", "foobar"}},
+	}
+	for _, c := range cases {
+		var end []byte
+		if !c.nilEnd {
+			end = []byte(c.end)
+		}
+		var got []string
+		it := r.Root().Range([]byte(c.start), end)
+		for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+			got = append(got, string(k))
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("[%q, %q): got %q, want %q", c.start, c.end, got, c.want)
+		}
+		if _, _, ok := it.Next(); ok {
+			t.Fatalf("iterator should stay done")
+		}
+	}
+}
+
 func TestIterateLowerBoundFuzz(t *testing.T) {
 	r := New[any]()
 	var set []string
@@ -1900,6 +2208,117 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCommit_OldTreeUnchanged(t *testing.T) {
+	r := New[int]()
+	keys := []string{"", "AB", "ABC", "AR", "R", "RA", "foo", "foo/bar", "foobar"}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	txn := r.Txn(false)
+	txn.Delete([]byte("foo"))
+	txn.DeletePrefix([]byte("AR"))
+	txn.Insert([]byte("ABD"), 100)
+	txn.Insert([]byte("R"), 100)
+	r2 := txn.Commit()
+	r3, _ := r2.DeletePrefix([]byte("A"))
+
+	// Every earlier version still reads the same.
+	verifyTree(t, keys, r)
+	for i, k := range keys {
+		if v, ok := r.Get([]byte(k)); !ok || v != i {
+			t.Fatalf("bad value for %q: %v %v", k, v, ok)
+		}
+	}
+	verifyTree(t, []string{"", "AB", "ABC", "ABD", "R", "RA", "foo/bar", "foobar"}, r2)
+	verifyTree(t, []string{"", "R", "RA", "foo/bar", "foobar"}, r3)
+}
+
+func TestSnapshot_SurvivesLaterTxn(t *testing.T) {
+	keys := []string{"", "AB", "ABC", "AR", "R", "RA", "foo", "foo/bar", "foobar"}
+	for _, commitOnly := range []bool{false, true} {
+		txn := New[int]().Txn(false)
+		for i, k := range keys {
+			txn.Insert([]byte(k), i)
+		}
+		var snap *Tree[int]
+		if commitOnly {
+			snap = txn.CommitOnly()
+		} else {
+			snap = txn.Commit()
+		}
+
+		// Later transactions on the snapshot must copy its nodes rather
+		// than write them in place, including when they drop whole
+		// subtrees.
+		for _, prefix := range []string{"A", "foo", ""} {
+			txn := snap.Txn(false)
+			txn.DeletePrefix([]byte(prefix))
+			txn.Insert([]byte("ABD"), 100)
+			txn.Delete([]byte("R"))
+			txn.CommitOnly()
+		}
+		verifyTree(t, keys, snap)
+		for i, k := range keys {
+			if v, ok := snap.Get([]byte(k)); !ok || v != i {
+				t.Fatalf("bad value for %q: %v %v", k, v, ok)
+			}
+		}
+	}
+}
+
+func TestDeepClone(t *testing.T) {
+	type value struct {
+		n int
+	}
+	r := New[*value]()
+	keys := []string{"foo", "foobar", "foo/bar", "zip"}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), &value{i})
+	}
+	watch, _, _ := r.Root().GetWatch([]byte("foo"))
+
+	c := r.DeepClone(func(v *value) *value {
+		nv := *v
+		return &nv
+	})
+	if c.Len() != r.Len() {
+		t.Fatalf("bad len: %d", c.Len())
+	}
+
+	// Mutating the cloned values doesn't affect the original.
+	for i, k := range keys {
+		v, ok := c.Get([]byte(k))
+		if !ok || v.n != i {
+			t.Fatalf("bad value for %q", k)
+		}
+		orig, _ := r.Get([]byte(k))
+		if v == orig {
+			t.Fatalf("value for %q is shared", k)
+		}
+		v.n = 100
+		if orig.n != i {
+			t.Fatalf("original value for %q changed", k)
+		}
+	}
+
+	// Neither do changes to the clone's structure, and they don't fire the
+	// original's watches.
+	txn := c.Txn(false)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("foo"), &value{42})
+	txn.Delete([]byte("zip"))
+	txn.Commit()
+	if _, ok := r.Get([]byte("zip")); !ok {
+		t.Fatalf("original tree changed")
+	}
+	select {
+	case <-watch:
+		t.Fatalf("original watch fired")
+	default:
+	}
+}
+
 const datasetSize = 100000
 
 func generateDataset(size int) []string {
@@ -2118,3 +2537,146 @@ func BenchmarkSeekReverseLowerBound(b *testing.B) {
 		}
 	}
 }
+
+func TestIterator_SkipPrefix(t *testing.T) {
+	keys := []string{
+		"a", "ns1/", "ns1/a", "ns1/b/c", "ns1/c", "ns10", "ns2/a", "ns2/b", "z",
+	}
+	r := New[int]()
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	collect := func(it *Iterator[int]) []string {
+		var out []string
+		for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+			out = append(out, string(k))
+		}
+		return out
+	}
+
+	cases := []struct {
+		seek string
+		skip string
+		want []string
+	}{
+		{"ns1/", "ns1/", []string{"ns10", "ns2/a", "ns2/b", "z"}},
+		{"ns1/b", "ns1/", []string{"ns10", "ns2/a", "ns2/b", "z"}},
+		{"ns1/b", "ns1/b", []string{"ns1/c", "ns10", "ns2/a", "ns2/b", "z"}},
+		{"ns1", "ns1", []string{"ns2/a", "ns2/b", "z"}},
+		{"ns2/a", "ns", []string{"z"}},
+		{"", "", nil},
+		// Nothing is skipped unless the next key is under the prefix.
+		{"a", "ns1/", []string{"a", "ns1/", "ns1/a", "ns1/b/c", "ns1/c", "ns10", "ns2/a", "ns2/b", "z"}},
+		{"ns10", "ns1/", []string{"ns10", "ns2/a", "ns2/b", "z"}},
+	}
+	for _, tc := range cases {
+		it := r.Root().Iterator()
+		it.SeekLowerBound([]byte(tc.seek))
+		it.SkipPrefix([]byte(tc.skip))
+		if got := collect(it); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("seek %q skip %q: got %v want %v", tc.seek, tc.skip, got, tc.want)
+		}
+	}
+
+	// Skipping mid-iteration, after the first key of a namespace.
+	it := r.Root().Iterator()
+	var got []string
+	for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+		got = append(got, string(k))
+		if strings.HasPrefix(string(k), "ns") {
+			it.SkipPrefix(k[:4])
+		}
+	}
+	want := []string{"a", "ns1/", "ns10", "ns2/a", "z"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	// Every seek and prefix matches skipping the keys one by one.
+	var all []string
+	for _, k := range keys {
+		for j := 0; j <= len(k); j++ {
+			all = append(all, k[:j], k[:j]+"x")
+		}
+	}
+	for _, seek := range all {
+		for _, skip := range all {
+			it := r.Root().Iterator()
+			it.SeekLowerBound([]byte(seek))
+			it.SkipPrefix([]byte(skip))
+			got := collect(it)
+
+			var want []string
+			skipping := true
+			for _, k := range keys {
+				if k < seek {
+					continue
+				}
+				if skipping && strings.HasPrefix(k, skip) {
+					continue
+				}
+				skipping = false
+				want = append(want, k)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("seek %q skip %q: got %v want %v", seek, skip, got, want)
+			}
+		}
+	}
+}
+
+func TestWalkPrefixBackwards(t *testing.T) {
+	r := New[any]()
+	keys := []string{"foobar", "foo/bar/baz", "foo/baz/bar", "foo/zip/zap", "zipzap"}
+	for _, k := range keys {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+
+	cases := map[string][]string{
+		"":        {"zipzap", "foobar", "foo/zip/zap", "foo/baz/bar", "foo/bar/baz"},
+		"foo":     {"foobar", "foo/zip/zap", "foo/baz/bar", "foo/bar/baz"},
+		"foo/":    {"foo/zip/zap", "foo/baz/bar", "foo/bar/baz"},
+		"foo/ba":  {"foo/baz/bar", "foo/bar/baz"},
+		"foobar":  {"foobar"},
+		"foobarz": nil,
+		"x":       nil,
+	}
+	for prefix, want := range cases {
+		var got []string
+		r.Root().WalkPrefixBackwards([]byte(prefix), func(k []byte, _ any) bool {
+			got = append(got, string(k))
+			return false
+		})
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("prefix %q: got %v want %v", prefix, got, want)
+		}
+	}
+}
+
+func TestWalkPathBackwards(t *testing.T) {
+	r := New[any]()
+	keys := []string{"foo", "foo/bar", "foo/bar/baz", "foo/baz/bar", "zipzap"}
+	for _, k := range keys {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+
+	var got []string
+	r.Root().WalkPathBackwards([]byte("foo/bar/baz/zip"), func(k []byte, _ any) bool {
+		got = append(got, string(k))
+		return false
+	})
+	want := []string{"foo/bar/baz", "foo/bar", "foo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	got = nil
+	r.Root().WalkPathBackwards([]byte("foo/bar/baz"), func(k []byte, _ any) bool {
+		got = append(got, string(k))
+		return true
+	})
+	if !reflect.DeepEqual(got, []string{"foo/bar/baz"}) {
+		t.Fatalf("should stop early: %v", got)
+	}
+}
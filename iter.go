@@ -9,6 +9,10 @@ import (
 type Iterator[T any] struct {
 	node  *Node[T]
 	stack []edges[T]
+
+	// end is the key the iteration stops at, excluded, or nil to run to
+	// the end, see SplitIterators.
+	end []byte
 }
 
 // SeekPrefixWatch is used to seek the iterator to a given prefix
@@ -34,7 +38,7 @@ func (i *Iterator[T]) SeekPrefixWatch(prefix []byte) (watch <-chan struct{}) {
 		}
 
 		// Update to the finest granularity as the search makes progress
-		watch = n.getMutateCh()
+		watch = n.watchCh(watch)
 
 		// Consume the search prefix
 		if bytes.HasPrefix(search, n.prefix) {
@@ -170,6 +174,15 @@ func (i *Iterator[T]) SeekLowerBound(key []byte) {
 	}
 }
 
+// SeekRange is used to seek the iterator to the smallest key that is greater
+// or equal to start, like SeekLowerBound, and make it stop before the first
+// key that is greater or equal to end. A nil end doesn't bound the iteration.
+// The bound is kept by later seeks, so they should stay below it.
+func (i *Iterator[T]) SeekRange(start, end []byte) {
+	i.SeekLowerBound(start)
+	i.end = end
+}
+
 // Next returns the next node in order
 func (i *Iterator[T]) Next() ([]byte, T, bool) {
 	var zero T
@@ -183,6 +196,7 @@ func (i *Iterator[T]) Next() ([]byte, T, bool) {
 		n := len(i.stack)
 		last := i.stack[n-1]
 		elem := last[0].node
+		elem.auditCheck()
 
 		// Update the stack
 		if len(last) > 1 {
@@ -198,8 +212,97 @@ func (i *Iterator[T]) Next() ([]byte, T, bool) {
 
 		// Return the leaf values if any
 		if elem.leaf != nil {
+			if i.end != nil && bytes.Compare(elem.leaf.key, i.end) >= 0 {
+				i.stack = nil
+				i.node = nil
+				return nil, zero, false
+			}
 			return elem.leaf.key, elem.leaf.val, true
 		}
 	}
 	return nil, zero, false
 }
+
+// SkipPrefix is used mid-iteration to jump past the remaining keys under the
+// given prefix without visiting them, so that a namespace found not to be
+// needed can be skipped. It only skips anything if the next key is under the
+// prefix, whole subtrees being dropped without being descended into.
+func (i *Iterator[T]) SkipPrefix(prefix []byte) {
+	// Initialize our stack if needed
+	if i.stack == nil && i.node != nil {
+		i.stack = []edges[T]{{edge[T]{node: i.node}}}
+	}
+
+	// base is the key of the parent of the nodes at the top of the stack,
+	// which is found once for each level of the stack reached, and level is
+	// the length of the stack it is valid for.
+	var base []byte
+	level := -1
+	for len(i.stack) > 0 {
+		n := len(i.stack)
+		last := i.stack[n-1]
+		elem := last[0].node
+		if level != n {
+			var ok bool
+			if base, ok = parentKey(elem); !ok {
+				// An empty root has nothing to skip
+				i.stack = i.stack[:n-1]
+				continue
+			}
+			level = n
+		}
+
+		// Keys are visited in order, so once the next key is past the prefix
+		// there is nothing left to skip.
+		under := false
+		if len(prefix) <= len(base) {
+			if !bytes.HasPrefix(base, prefix) {
+				return
+			}
+			under = true
+		} else {
+			if !bytes.HasPrefix(prefix, base) {
+				return
+			}
+			rest := prefix[len(base):]
+			if bytes.HasPrefix(elem.prefix, rest) {
+				under = true
+			} else if !bytes.HasPrefix(rest, elem.prefix) || elem.leaf != nil {
+				// The node's own leaf is the next key, and is shorter
+				// than the prefix
+				return
+			}
+		}
+
+		// Drop the node from the stack
+		if len(last) > 1 {
+			i.stack[n-1] = last[1:]
+		} else {
+			i.stack = i.stack[:n-1]
+		}
+
+		// If the whole subtree is under the prefix it is skipped, otherwise
+		// the prefix goes on below it and its edges are checked in turn.
+		if !under && len(elem.edges) > 0 {
+			base = concat(base, elem.prefix)
+			i.stack = append(i.stack, elem.edges)
+			level = len(i.stack)
+		}
+	}
+}
+
+// parentKey returns the key of the path leading to n, without n's own prefix,
+// from the first leaf under it, or false if there is none.
+func parentKey[T any](n *Node[T]) ([]byte, bool) {
+	below := len(n.prefix)
+	for m := n; ; {
+		if m.leaf != nil {
+			return m.leaf.key[:len(m.leaf.key)-below], true
+		}
+		if len(m.edges) == 0 {
+			return nil, false
+		}
+		m = m.edges[0].node
+		below += len(m.prefix)
+	}
+}
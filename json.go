@@ -0,0 +1,103 @@
+package iradix
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+)
+
+// JSONKeyEncoding selects how WriteJSON turns keys into JSON strings.
+type JSONKeyEncoding int
+
+const (
+	// JSONKeyString writes keys as strings. Bytes that aren't valid UTF-8
+	// are replaced, so binary keys should use another encoding.
+	JSONKeyString JSONKeyEncoding = iota
+
+	// JSONKeyBase64 writes keys in standard base64.
+	JSONKeyBase64
+
+	// JSONKeyHex writes keys in hexadecimal.
+	JSONKeyHex
+)
+
+// JSONOptions configures WriteJSON.
+type JSONOptions struct {
+	// Array writes an array of {"key": ..., "value": ...} objects rather
+	// than a single object mapping the keys to the values.
+	Array bool
+
+	// TrimPrefix removes the prefix from the keys written.
+	TrimPrefix bool
+
+	// KeyEncoding selects how keys are encoded.
+	KeyEncoding JSONKeyEncoding
+}
+
+// jsonEntry is an entry written by WriteJSON in array mode.
+type jsonEntry[T any] struct {
+	Key   string `json:"key"`
+	Value T      `json:"value"`
+}
+
+// WriteJSON streams the entries under the given prefix to w as JSON, in key
+// order, encoding each value with encoding/json as it goes rather than
+// building an intermediate map, for "dump this namespace" endpoints. An
+// error from encoding a value or from w stops the scan and is returned.
+func WriteJSON[T any](w io.Writer, n *Node[T], prefix []byte, opts JSONOptions) error {
+	bw := bufio.NewWriter(w)
+	start, end := byte('{'), byte('}')
+	if opts.Array {
+		start, end = '[', ']'
+	}
+	bw.WriteByte(start)
+
+	var err error
+	first := true
+	n.WalkPrefix(prefix, func(k []byte, v T) bool {
+		if opts.TrimPrefix {
+			k = k[len(prefix):]
+		}
+		key := encodeJSONKey(k, opts.KeyEncoding)
+
+		var b []byte
+		if opts.Array {
+			b, err = json.Marshal(jsonEntry[T]{Key: key, Value: v})
+		} else {
+			b, err = json.Marshal(v)
+		}
+		if err != nil {
+			return true
+		}
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		if !opts.Array {
+			kb, _ := json.Marshal(key)
+			bw.Write(kb)
+			bw.WriteByte(':')
+		}
+		_, err = bw.Write(b)
+		return err != nil
+	})
+	if err != nil {
+		return err
+	}
+	bw.WriteByte(end)
+	return bw.Flush()
+}
+
+// encodeJSONKey returns the JSON string for a key.
+func encodeJSONKey(k []byte, enc JSONKeyEncoding) string {
+	switch enc {
+	case JSONKeyBase64:
+		return base64.StdEncoding.EncodeToString(k)
+	case JSONKeyHex:
+		return hex.EncodeToString(k)
+	default:
+		return string(k)
+	}
+}
@@ -0,0 +1,55 @@
+package iradix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("boom")
+}
+
+func TestWriteJSON(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	r := New[user]()
+	r, _, _ = r.Insert([]byte("users/1"), user{"ann"})
+	r, _, _ = r.Insert([]byte("users/2"), user{"bob"})
+	r, _, _ = r.Insert([]byte("groups/1"), user{"x"})
+
+	cases := []struct {
+		prefix string
+		opts   JSONOptions
+		want   string
+	}{
+		{"users/", JSONOptions{}, `{"users/1":{"name":"ann"},"users/2":{"name":"bob"}}`},
+		{"users/", JSONOptions{TrimPrefix: true}, `{"1":{"name":"ann"},"2":{"name":"bob"}}`},
+		{"users/", JSONOptions{Array: true, TrimPrefix: true}, `[{"key":"1","value":{"name":"ann"}},{"key":"2","value":{"name":"bob"}}]`},
+		{"groups/", JSONOptions{KeyEncoding: JSONKeyHex}, `{"67726f7570732f31":{"name":"x"}}`},
+		{"groups/", JSONOptions{KeyEncoding: JSONKeyBase64}, `{"Z3JvdXBzLzE=":{"name":"x"}}`},
+		{"none/", JSONOptions{}, `{}`},
+		{"none/", JSONOptions{Array: true}, `[]`},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		if err := WriteJSON(&buf, r.Root(), []byte(tc.prefix), tc.opts); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if buf.String() != tc.want {
+			t.Fatalf("prefix %q %+v: got %s want %s", tc.prefix, tc.opts, buf.String(), tc.want)
+		}
+	}
+
+	bad := New[any]()
+	bad, _, _ = bad.Insert([]byte("f"), func() {})
+	if err := WriteJSON(&bytes.Buffer{}, bad.Root(), nil, JSONOptions{}); err == nil {
+		t.Fatalf("expected an encoding error")
+	}
+	if err := WriteJSON(failingWriter{}, r.Root(), nil, JSONOptions{}); err == nil {
+		t.Fatalf("expected a write error")
+	}
+}
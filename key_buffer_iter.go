@@ -0,0 +1,94 @@
+package iradix
+
+import "bytes"
+
+// KeyBufferIterator is an iterator that rebuilds each key from the edge
+// prefixes along its path into a single buffer, instead of returning the key
+// stored in the leaf. The buffer is reused, so the key returned by Next is
+// only valid until the following call and must be copied to be retained. In
+// exchange, iterating allocates nothing once the buffer has grown to the
+// longest key, and the keys stored in the leaves are never read, which suits
+// high throughput consumers that only hash or compare the keys.
+type KeyBufferIterator[T any] struct {
+	node  *Node[T]
+	base  []byte
+	buf   []byte
+	stack []keyBufferEntry[T]
+}
+
+// keyBufferEntry holds the edges left to visit below a node, and the length of
+// the buffer holding the node's path.
+type keyBufferEntry[T any] struct {
+	n     int
+	edges edges[T]
+}
+
+// KeyBufferIterator returns a KeyBufferIterator over the keys under the node,
+// which must be the root for the keys to be complete, unless it is seeked
+// from the root with SeekPrefix.
+func (n *Node[T]) KeyBufferIterator() *KeyBufferIterator[T] {
+	return &KeyBufferIterator[T]{node: n}
+}
+
+// SeekPrefix is used to seek the iterator to a given prefix
+func (i *KeyBufferIterator[T]) SeekPrefix(prefix []byte) {
+	i.stack = nil
+	i.base = i.base[:0]
+	n := i.node
+	search := prefix
+	for len(search) > 0 {
+		// The path above the node is kept as the base of its keys
+		i.base = append(i.base, n.prefix...)
+		_, n = n.getEdge(search[0])
+		if n == nil {
+			break
+		}
+		if bytes.HasPrefix(search, n.prefix) {
+			search = search[len(n.prefix):]
+		} else if bytes.HasPrefix(n.prefix, search) {
+			break
+		} else {
+			n = nil
+			break
+		}
+	}
+	i.node = n
+	if n == nil {
+		// Leave an empty, non-nil stack so that Next finds nothing
+		i.stack = []keyBufferEntry[T]{}
+	}
+}
+
+// Next returns the next key in order, in a buffer that is only valid until
+// the next call, see KeyBufferIterator.
+func (i *KeyBufferIterator[T]) Next() ([]byte, T, bool) {
+	// Initialize our stack if needed
+	if i.stack == nil && i.node != nil {
+		i.buf = append(i.buf[:0], i.base...)
+		i.stack = []keyBufferEntry[T]{{n: len(i.buf), edges: edges[T]{{node: i.node}}}}
+	}
+
+	for len(i.stack) > 0 {
+		last := &i.stack[len(i.stack)-1]
+		elem := last.edges[0].node
+		i.buf = append(i.buf[:last.n], elem.prefix...)
+
+		// Update the stack
+		if len(last.edges) > 1 {
+			last.edges = last.edges[1:]
+		} else {
+			i.stack = i.stack[:len(i.stack)-1]
+		}
+
+		// Push the edges onto the frontier
+		if len(elem.edges) > 0 {
+			i.stack = append(i.stack, keyBufferEntry[T]{n: len(i.buf), edges: elem.edges})
+		}
+
+		if elem.leaf != nil {
+			return i.buf, elem.leaf.val, true
+		}
+	}
+	var zero T
+	return nil, zero, false
+}
@@ -0,0 +1,55 @@
+package iradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeyBufferIterator(t *testing.T) {
+	txn := New[int]().Txn(false)
+	for i := 0; i < 500; i++ {
+		txn.Insert([]byte(fmt.Sprintf("k%d/%d", i%7, i)), i)
+	}
+	txn.Insert([]byte(""), -1)
+	txn.Insert([]byte("k1"), -2)
+	r := txn.Commit()
+
+	check := func(it *KeyBufferIterator[int], prefix string) {
+		t.Helper()
+		want := r.Root().Iterator()
+		want.SeekPrefix([]byte(prefix))
+		for {
+			wk, wv, wok := want.Next()
+			k, v, ok := it.Next()
+			if ok != wok || string(k) != string(wk) || v != wv {
+				t.Fatalf("%q: got %q %d %v, want %q %d %v", prefix, k, v, ok, wk, wv, wok)
+			}
+			if !ok {
+				return
+			}
+		}
+	}
+
+	check(r.Root().KeyBufferIterator(), "")
+	for _, prefix := range []string{"", "k", "k1", "k1/", "k3/1", "k3/12", "k9", "x"} {
+		it := r.Root().KeyBufferIterator()
+		it.SeekPrefix([]byte(prefix))
+		check(it, prefix)
+	}
+}
+
+func BenchmarkKeyBufferIterator(b *testing.B) {
+	txn := New[int]().Txn(false)
+	for i := 0; i < 10000; i++ {
+		txn.Insert([]byte(fmt.Sprintf("key/%d/%d", i%13, i)), i)
+	}
+	root := txn.Commit().Root()
+	b.ReportAllocs()
+	b.ResetTimer()
+	it := root.KeyBufferIterator()
+	for i := 0; i < b.N; i++ {
+		if _, _, ok := it.Next(); !ok {
+			it = root.KeyBufferIterator()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package iradix
+
+import "time"
+
+// Modified is the last modification of a prefix, see WithLastModified.
+type Modified struct {
+	// Version is the generation of the root of the tree committed by the
+	// modification, see Tree.Generation. It only ever increases.
+	Version uint64
+
+	// Time is when the modification was committed.
+	Time time.Time
+}
+
+// WithLastModified makes the tree track the last modification of the keys
+// under each prefix of the given length, the top-level namespaces, so that
+// consumers can check the freshness of their caches of a namespace with
+// LastModified rather than by diffing the tree. Keys shorter than depth are
+// tracked as their own prefix. The time of a modification is given by clock,
+// or time.Now if it is nil.
+func WithLastModified[T any](depth int, clock func() time.Time) Option[T] {
+	return func(c *config[T]) {
+		c.lastModDepth = depth
+		c.clock = clock
+	}
+}
+
+// trackedPrefix returns the tracked prefix a key is under.
+func (t *Txn[T]) trackedPrefix(k []byte) []byte {
+	return k[:min(len(k), t.conf.lastModDepth)]
+}
+
+// touch records that the tracked prefix of a key was modified.
+func (t *Txn[T]) touch(k []byte) {
+	if t.touched == nil {
+		t.touched = make(map[string]struct{})
+	}
+	t.touched[string(t.trackedPrefix(k))] = struct{}{}
+}
+
+// touchPrefix records that the keys under a prefix were deleted. If it is
+// shorter than the tracked prefixes, every tracked prefix under it may have
+// been modified. Since the tracked prefix of every key ever inserted is
+// recorded, they are found without visiting the keys.
+func (t *Txn[T]) touchPrefix(prefix []byte) {
+	if len(prefix) >= t.conf.lastModDepth {
+		t.touch(prefix)
+		return
+	}
+	t.lastMod.Root().WalkPrefix(prefix, func(p []byte, _ Modified) bool {
+		t.touch(p)
+		return false
+	})
+}
+
+// commitLastModified returns the last modifications with the prefixes touched
+// by the transaction updated to the given version.
+func (t *Txn[T]) commitLastModified(version uint64) *Tree[Modified] {
+	if len(t.touched) == 0 {
+		return t.lastMod
+	}
+	clock := t.conf.clock
+	if clock == nil {
+		clock = time.Now
+	}
+	m := Modified{Version: version, Time: clock()}
+	txn := t.lastMod.Txn(false)
+	for p := range t.touched {
+		txn.Insert([]byte(p), m)
+	}
+	return txn.Commit()
+}
+
+// LastModified returns the last modification of the keys under the given
+// prefix, and false if they were never modified or the tree wasn't created
+// with WithLastModified. For prefixes shorter than the tracked ones, it is the
+// latest modification of the tracked prefixes under it, and for longer ones
+// that of the tracked prefix they are under.
+func (t *Tree[T]) LastModified(prefix []byte) (Modified, bool) {
+	if t.lastMod == nil {
+		return Modified{}, false
+	}
+	if len(prefix) >= t.conf.lastModDepth {
+		return t.lastMod.Get(prefix[:t.conf.lastModDepth])
+	}
+	var last Modified
+	var found bool
+	t.lastMod.Root().WalkPrefix(prefix, func(_ []byte, m Modified) bool {
+		if !found || m.Version > last.Version {
+			last, found = m, true
+		}
+		return false
+	})
+	return last, found
+}
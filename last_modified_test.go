@@ -0,0 +1,66 @@
+package iradix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastModified(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	r := New[int](WithLastModified[int](3, clock))
+	if _, ok := r.LastModified([]byte("abc")); ok {
+		t.Fatalf("nothing modified yet")
+	}
+
+	txn := r.Txn(false)
+	txn.Insert([]byte("abc/1"), 1)
+	txn.Insert([]byte("abd/1"), 2)
+	txn.Insert([]byte("xy"), 3)
+	r = txn.Commit()
+	first, ok := r.LastModified([]byte("abc"))
+	if !ok || first.Time != time.Unix(1001, 0) || first.Version != r.Generation() {
+		t.Fatalf("bad: %v %v", first, ok)
+	}
+	if m, _ := r.LastModified([]byte("abc/1/2")); m != first {
+		t.Fatalf("longer prefixes should use their tracked prefix: %v", m)
+	}
+	if m, ok := r.LastModified([]byte("xy")); !ok || m != first {
+		t.Fatalf("short keys are their own prefix: %v", m)
+	}
+
+	r, _, _ = r.Insert([]byte("abd/2"), 4)
+	second, _ := r.LastModified([]byte("abd"))
+	if second.Version <= first.Version || !second.Time.After(first.Time) {
+		t.Fatalf("bad: %v", second)
+	}
+	if m, _ := r.LastModified([]byte("abc")); m != first {
+		t.Fatalf("abc wasn't modified: %v", m)
+	}
+	if m, _ := r.LastModified([]byte("ab")); m != second {
+		t.Fatalf("should be the latest under ab: %v", m)
+	}
+	if m, _ := r.LastModified(nil); m != second {
+		t.Fatalf("should be the latest overall: %v", m)
+	}
+
+	// Deleting a short prefix modifies every tracked prefix under it.
+	r, _ = r.DeletePrefix([]byte("a"))
+	third, _ := r.LastModified([]byte("abc"))
+	if third.Version <= second.Version {
+		t.Fatalf("bad: %v", third)
+	}
+	if m, _ := r.LastModified([]byte("abd")); m != third {
+		t.Fatalf("bad: %v", m)
+	}
+	if m, _ := r.LastModified([]byte("xy")); m != first {
+		t.Fatalf("xy wasn't modified: %v", m)
+	}
+
+	if _, ok := New[int]().LastModified(nil); ok {
+		t.Fatalf("not tracked")
+	}
+}
@@ -0,0 +1,111 @@
+package iradix
+
+import "sync"
+
+// Loader is used to load the value of a key that was dropped from memory, see
+// Lazy.
+type Loader[T any] func(k []byte) (T, error)
+
+// Lazy is a value that can be dropped from memory and loaded again when it is
+// next read, for trees keeping a large keyspace resident while paging heavy
+// values from elsewhere. A tree of lazy values stores *Lazy[T], so the leaves
+// keep the small stub while the value itself comes and goes. Since the stub is
+// shared by every snapshot holding it, dropping a value affects all of them,
+// which is fine as long as the loader returns the same value.
+//
+// A Lazy is safe for concurrent use.
+type Lazy[T any] struct {
+	key  []byte
+	load Loader[T]
+
+	l        sync.Mutex
+	val      T
+	resident bool
+}
+
+// NewLazy returns a resident lazy value for the key, loaded again with load
+// once dropped.
+func NewLazy[T any](k []byte, v T, load Loader[T]) *Lazy[T] {
+	return &Lazy[T]{key: k, load: load, val: v, resident: true}
+}
+
+// Value returns the value, loading it if it was dropped. A loading error is
+// returned as is and the value stays dropped.
+func (l *Lazy[T]) Value() (T, error) {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if !l.resident {
+		v, err := l.load(l.key)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		l.val, l.resident = v, true
+	}
+	return l.val, nil
+}
+
+// Resident returns if the value is held in memory.
+func (l *Lazy[T]) Resident() bool {
+	l.l.Lock()
+	defer l.l.Unlock()
+	return l.resident
+}
+
+// Drop releases the value, leaving the stub. It returns the size of the
+// released value according to fn, or zero if it wasn't resident. fn may be nil
+// when the size isn't needed.
+func (l *Lazy[T]) Drop(fn Sizer[T]) int {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if !l.resident {
+		return 0
+	}
+	size := 0
+	if fn != nil {
+		size = fn(l.val)
+	}
+	var zero T
+	l.val, l.resident = zero, false
+	return size
+}
+
+// LazySizer returns a Sizer for lazy values, counting the resident ones with
+// fn and the dropped ones as zero. Use it with WithSizer to account for the
+// resident values. Sizes are computed when transactions are committed, so
+// values dropped or loaded since then are only accounted for once their keys
+// are written again; use ResidentSize for the current figure.
+func LazySizer[T any](fn Sizer[T]) Sizer[*Lazy[T]] {
+	return func(l *Lazy[T]) int {
+		l.l.Lock()
+		defer l.l.Unlock()
+		if !l.resident {
+			return 0
+		}
+		return fn(l.val)
+	}
+}
+
+// DropLazy drops the resident lazy values under the given prefix, typically
+// in response to memory pressure, and returns the total size released
+// according to fn.
+func DropLazy[T any](n *Node[*Lazy[T]], prefix []byte, fn Sizer[T]) int64 {
+	var freed int64
+	n.WalkPrefix(prefix, func(_ []byte, l *Lazy[T]) bool {
+		freed += int64(l.Drop(fn))
+		return false
+	})
+	return freed
+}
+
+// ResidentSize returns the total size according to fn of the lazy values under
+// the given prefix that are currently held in memory.
+func ResidentSize[T any](n *Node[*Lazy[T]], prefix []byte, fn Sizer[T]) int64 {
+	sizer := LazySizer(fn)
+	var size int64
+	n.WalkPrefix(prefix, func(_ []byte, l *Lazy[T]) bool {
+		size += int64(sizer(l))
+		return false
+	})
+	return size
+}
@@ -0,0 +1,65 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	store := map[string]string{
+		"big/a": "aaaaaaaaaa",
+		"big/b": "bbbbbbbbbbbbbbbbbbbb",
+		"small": "s",
+	}
+	loads := 0
+	load := func(k []byte) (string, error) {
+		loads++
+		v, ok := store[string(k)]
+		if !ok {
+			return "", errors.New("missing")
+		}
+		return v, nil
+	}
+	size := func(v string) int { return len(v) }
+
+	txn := New[*Lazy[string]](WithSizer(LazySizer(size))).Txn(false)
+	for k, v := range store {
+		txn.Insert([]byte(k), NewLazy([]byte(k), v, load))
+	}
+	r := txn.Commit()
+	if got := r.Root().Size(); got != 31 {
+		t.Fatalf("bad size: %d", got)
+	}
+
+	if freed := DropLazy(r.Root(), []byte("big/"), size); freed != 30 {
+		t.Fatalf("bad freed: %d", freed)
+	}
+	if freed := DropLazy(r.Root(), []byte("big/"), size); freed != 0 {
+		t.Fatalf("dropped twice: %d", freed)
+	}
+	if got := ResidentSize(r.Root(), nil, size); got != 1 {
+		t.Fatalf("bad resident size: %d", got)
+	}
+
+	l, _ := r.Get([]byte("big/b"))
+	if l.Resident() {
+		t.Fatalf("should be dropped")
+	}
+	v, err := l.Value()
+	if err != nil || v != store["big/b"] || loads != 1 {
+		t.Fatalf("bad: %q %v %d", v, err, loads)
+	}
+	if _, _ = l.Value(); loads != 1 || !l.Resident() {
+		t.Fatalf("should stay resident: %d", loads)
+	}
+	if got := ResidentSize(r.Root(), nil, size); got != 21 {
+		t.Fatalf("bad resident size: %d", got)
+	}
+
+	// Loading errors leave the value dropped.
+	gone := NewLazy([]byte("gone"), "x", load)
+	gone.Drop(nil)
+	if _, err := gone.Value(); err == nil || gone.Resident() {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
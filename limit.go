@@ -0,0 +1,51 @@
+package iradix
+
+import "errors"
+
+// ErrMaxTxnMutations is returned for a mutation rejected because the
+// transaction already made the maximum number of mutations.
+var ErrMaxTxnMutations = errors.New("transaction mutation limit reached")
+
+// WithMaxTxnMutations limits the number of mutations a single transaction can
+// make to n, protecting shared trees from giant commits that stall the
+// notification fan-out. Each insert and delete counts as one mutation, and
+// DeletePrefix counts one per key deleted. Once the limit is reached further
+// mutations are not applied: TryInsert, TryDelete and the bulk operations
+// return ErrMaxTxnMutations, and Txn.Err reports it for the others. The
+// mutations made before the limit can still be committed with Commit, which
+// silently loses the rejected ones, while TryCommit refuses to.
+func WithMaxTxnMutations[T any](n int) Option[T] {
+	return func(c *config[T]) {
+		c.maxTxnMutations = n
+	}
+}
+
+// countMutation counts a mutation about to be made against the transaction's
+// limit, returning ErrMaxTxnMutations if it is already full.
+func (t *Txn[T]) countMutation() error {
+	if err := t.checkMutationLimit(); err != nil {
+		return err
+	}
+	t.addMutations(1)
+	return nil
+}
+
+// checkMutationLimit returns ErrMaxTxnMutations if the transaction is already
+// full, without counting a mutation.
+func (t *Txn[T]) checkMutationLimit() error {
+	if t.conf == nil || t.conf.maxTxnMutations <= 0 {
+		return nil
+	}
+	if t.mutations >= t.conf.maxTxnMutations {
+		return t.reject(ErrMaxTxnMutations)
+	}
+	return nil
+}
+
+// addMutations counts n mutations made against the transaction's limit.
+func (t *Txn[T]) addMutations(n int) {
+	if t.conf == nil || t.conf.maxTxnMutations <= 0 {
+		return
+	}
+	t.mutations += n
+}
@@ -0,0 +1,74 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxTxnMutations(t *testing.T) {
+	r := New[int](WithMaxTxnMutations[int](3))
+
+	txn := r.Txn(false)
+	for _, k := range []string{"a", "b", "c"} {
+		if _, _, err := txn.TryInsert([]byte(k), 1); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	if _, _, err := txn.TryInsert([]byte("d"), 1); !errors.Is(err, ErrMaxTxnMutations) {
+		t.Fatalf("bad: %v", err)
+	}
+	if _, ok := txn.Delete([]byte("a")); ok {
+		t.Fatalf("delete should be rejected")
+	}
+	if !errors.Is(txn.Err(), ErrMaxTxnMutations) {
+		t.Fatalf("bad: %v", txn.Err())
+	}
+	if nt, err := txn.TryCommit(); nt != nil || !errors.Is(err, ErrMaxTxnMutations) {
+		t.Fatalf("TryCommit should refuse: %v", err)
+	}
+	r = txn.Commit()
+	if r.Len() != 3 {
+		t.Fatalf("bad len: %d", r.Len())
+	}
+
+	// The limit is per transaction, and DeletePrefix counts every key.
+	txn = r.Txn(false)
+	if _, ok, err := txn.TryDelete([]byte("a")); !ok || err != nil {
+		t.Fatalf("bad: %v %v", ok, err)
+	}
+	if !txn.DeletePrefix(nil) {
+		t.Fatalf("delete prefix should be applied")
+	}
+	if txn.DeletePrefix(nil) || txn.Len() != 0 {
+		t.Fatalf("delete prefix should be rejected")
+	}
+
+	// Prefixes matching nothing don't count, in either direction.
+	txn = r.Txn(false)
+	for i := 0; i < 5; i++ {
+		txn.DeletePrefix([]byte("zzz"))
+	}
+	if txn.mutations != 0 {
+		t.Fatalf("bad mutation count: %d", txn.mutations)
+	}
+	for _, k := range []string{"x", "y", "z"} {
+		txn.Insert([]byte(k), 1)
+	}
+	if _, _, err := txn.TryInsert([]byte("w"), 1); !errors.Is(err, ErrMaxTxnMutations) {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// A transaction without rejections commits normally.
+	txn = r.Txn(false)
+	txn.Insert([]byte("x"), 1)
+	if nt, err := txn.TryCommit(); err != nil || nt.Len() != 4 {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Bulk operations stop at the limit.
+	txn = New[int](WithMaxTxnMutations[int](2)).Txn(false)
+	n, err := txn.BulkInsert(seqKeys(5))
+	if n != 2 || !errors.Is(err, ErrMaxTxnMutations) {
+		t.Fatalf("bad: %d %v", n, err)
+	}
+}
@@ -0,0 +1,60 @@
+package iradix
+
+import (
+	"bytes"
+	"hash/maphash"
+	"sync/atomic"
+)
+
+// WithLookupCache gives every committed tree a cache of up to size recently
+// found leaves, which Tree.Get checks before walking down from the root. Since
+// committed trees are immutable the cache never needs invalidating; it is
+// populated lazily and dropped with the tree, so repeated Gets of the same hot
+// keys between commits are much cheaper. Misses aren't cached.
+//
+// The cache is direct-mapped: each key has a single slot, picked by its hash,
+// and a leaf found replaces whatever the slot held. This keeps concurrent Gets
+// lock-free, at the cost of hot keys that share a slot evicting each other.
+func WithLookupCache[T any](size int) Option[T] {
+	return func(c *config[T]) {
+		c.lookupCacheSize = size
+	}
+}
+
+// lookupCache maps the fingerprints of keys to the leaves found for them.
+type lookupCache[T any] struct {
+	seed  maphash.Seed
+	slots []atomic.Pointer[leafNode[T]]
+}
+
+// lookupCache returns the lookup cache of the tree, creating it on first use,
+// or nil if the tree has none.
+func (t *Tree[T]) lookupCache() *lookupCache[T] {
+	if t.conf == nil || t.conf.lookupCacheSize <= 0 {
+		return nil
+	}
+	if c := t.cache.Load(); c != nil {
+		return c
+	}
+	c := &lookupCache[T]{
+		seed:  maphash.MakeSeed(),
+		slots: make([]atomic.Pointer[leafNode[T]], t.conf.lookupCacheSize),
+	}
+	t.cache.CompareAndSwap(nil, c)
+	return t.cache.Load()
+}
+
+// get returns the leaf for the key, looking it up in the tree and caching it
+// if it isn't cached yet.
+func (c *lookupCache[T]) get(root *Node[T], k []byte) *leafNode[T] {
+	slot := &c.slots[maphash.Bytes(c.seed, k)%uint64(len(c.slots))]
+	if leaf := slot.Load(); leaf != nil && bytes.Equal(leaf.key, k) {
+		return leaf
+	}
+
+	leaf := root.leafFor(k)
+	if leaf != nil {
+		slot.Store(leaf)
+	}
+	return leaf
+}
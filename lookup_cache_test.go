@@ -0,0 +1,80 @@
+package iradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLookupCache(t *testing.T) {
+	r := New[int](WithLookupCache[int](2))
+	keys := []string{"", "a", "ab", "abc", "b"}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	for round := 0; round < 3; round++ {
+		for i, k := range keys {
+			if v, ok := r.Get([]byte(k)); !ok || v != i {
+				t.Fatalf("bad value for %q: %v %v", k, v, ok)
+			}
+		}
+		if _, ok := r.Get([]byte("abcd")); ok {
+			t.Fatalf("should be missing")
+		}
+	}
+	cached := 0
+	for i := range r.lookupCache().slots {
+		if r.lookupCache().slots[i].Load() != nil {
+			cached++
+		}
+	}
+	if cached == 0 || cached > 2 {
+		t.Fatalf("bad cache size: %d", cached)
+	}
+
+	// Each committed tree has its own cache.
+	r2, _, _ := r.Insert([]byte("a"), 10)
+	if v, _ := r2.Get([]byte("a")); v != 10 {
+		t.Fatalf("bad value: %d", v)
+	}
+	if v, _ := r.Get([]byte("a")); v != 1 {
+		t.Fatalf("old tree changed: %d", v)
+	}
+	r3, _, _ := r2.Delete([]byte("a"))
+	if _, ok := r3.Get([]byte("a")); ok {
+		t.Fatalf("should be deleted")
+	}
+
+	if New[int]().lookupCache() != nil {
+		t.Fatalf("no cache expected")
+	}
+}
+
+func BenchmarkLookupCache(b *testing.B) {
+	for _, size := range []int{0, 128} {
+		r := New[int](WithLookupCache[int](size))
+		txn := r.Txn(false)
+		var hot [][]byte
+		for i := 0; i < 100000; i++ {
+			k := []byte(fmt.Sprintf("key%05d", i))
+			txn.Insert(k, i)
+			if i%1000 == 0 {
+				hot = append(hot, k)
+			}
+		}
+		r = txn.Commit()
+		name := map[int]string{0: "off", 128: "on"}[size]
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				r.Get(hot[i%len(hot)])
+			}
+		})
+		b.Run(name+"-parallel", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					r.Get(hot[i%len(hot)])
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package iradix
+
+// MaskIterator iterates over the keys under a node that match a
+// fixed-position pattern, see Node.MaskIterator.
+type MaskIterator[T any] struct {
+	pattern []byte
+	mask    []byte
+	stack   []maskEntry[T]
+}
+
+// maskEntry holds the edges left to visit below a node, and the length of the
+// keys at the node.
+type maskEntry[T any] struct {
+	depth int
+	edges edges[T]
+}
+
+// MaskIterator returns an iterator over the keys under n that match a
+// fixed-position pattern, in order: a key matches if it is at least len(mask)
+// bytes long and key[i]&mask[i] == pattern[i]&mask[i] for every i <
+// len(mask). A zero mask byte leaves its position unconstrained, which suits
+// fixed-width composite keys where some fields don't matter. Subtrees that
+// can't match are skipped, and positions with a full 0xff mask byte are looked
+// up directly. The pattern and mask must have the same length.
+func (n *Node[T]) MaskIterator(pattern, mask []byte) *MaskIterator[T] {
+	if len(pattern) != len(mask) {
+		panic("iradix: pattern and mask lengths differ")
+	}
+	return &MaskIterator[T]{
+		pattern: pattern,
+		mask:    mask,
+		stack:   []maskEntry[T]{{edges: edges[T]{{node: n}}}},
+	}
+}
+
+// Next returns the next matching key in order.
+func (i *MaskIterator[T]) Next() ([]byte, T, bool) {
+	for len(i.stack) > 0 {
+		last := &i.stack[len(i.stack)-1]
+		elem := last.edges[0].node
+		depth := last.depth
+
+		// Update the stack
+		if len(last.edges) > 1 {
+			last.edges = last.edges[1:]
+		} else {
+			i.stack = i.stack[:len(i.stack)-1]
+		}
+		elem.auditCheck()
+		if !maskMatches(elem.prefix, depth, i.pattern, i.mask) {
+			continue
+		}
+		depth += len(elem.prefix)
+
+		// Push the edges that can match, past the end of the mask all of
+		// them do
+		if len(elem.edges) > 0 {
+			es := elem.edges
+			if depth < len(i.mask) && i.mask[depth] == 0xff {
+				idx, child := elem.getEdge(i.pattern[depth])
+				if child == nil {
+					es = nil
+				} else {
+					es = es[idx : idx+1]
+				}
+			}
+			if len(es) > 0 {
+				i.stack = append(i.stack, maskEntry[T]{depth: depth, edges: es})
+			}
+		}
+
+		if elem.leaf != nil && depth >= len(i.mask) {
+			return elem.leaf.key, elem.leaf.val, true
+		}
+	}
+	var zero T
+	return nil, zero, false
+}
+
+// WalkMask is used to walk the keys under n that match a fixed-position
+// pattern, see MaskIterator.
+func (n *Node[T]) WalkMask(pattern, mask []byte, fn WalkFn[T]) {
+	it := n.MaskIterator(pattern, mask)
+	for k, v, ok := it.Next(); ok; k, v, ok = it.Next() {
+		if fn(k, v) {
+			return
+		}
+	}
+}
+
+// maskMatches returns true if the bytes of part, which start at offset in the
+// key, match the pattern.
+func maskMatches(part []byte, offset int, pattern, mask []byte) bool {
+	for i, b := range part {
+		pos := offset + i
+		if pos >= len(mask) {
+			break
+		}
+		if b&mask[pos] != pattern[pos]&mask[pos] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWalkMask(t *testing.T) {
+	r := New[any]()
+	var keys []string
+	for _, tenant := range []string{"t001", "t002"} {
+		for _, kind := range []string{"user", "role"} {
+			for _, id := range []string{"0001", "0002"} {
+				k := tenant + kind + id
+				keys = append(keys, k)
+				r, _, _ = r.Insert([]byte(k), nil)
+			}
+		}
+	}
+	r, _, _ = r.Insert([]byte("t001"), nil)
+	r, _, _ = r.Insert([]byte("t001user0002extra"), nil)
+
+	full, skip := byte(0xff), byte(0)
+	mask := func(spec string) []byte {
+		var m []byte
+		for _, c := range spec {
+			if c == '?' {
+				m = append(m, skip)
+			} else {
+				m = append(m, full)
+			}
+		}
+		return m
+	}
+
+	cases := []struct {
+		pattern string
+		want    []string
+	}{
+		{"t001????0002", []string{"t001role0002", "t001user0002", "t001user0002extra"}},
+		{"????user0001", []string{"t001user0001", "t002user0001"}},
+		{"t00?role????", []string{"t001role0001", "t001role0002", "t002role0001", "t002role0002"}},
+		{"????????????", append(slices.Clone(keys), "t001user0002extra")},
+		{"t003????????", nil},
+		{"t001", append(slices.Clone(keys[:4]), "t001", "t001user0002extra")},
+	}
+	for _, c := range cases {
+		t.Run(c.pattern, func(t *testing.T) {
+			var got []string
+			r.Root().WalkMask([]byte(c.pattern), mask(c.pattern), func(k []byte, _ any) bool {
+				got = append(got, string(k))
+				return false
+			})
+			slices.Sort(got)
+			want := slices.Clone(c.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Partial byte masks compare only the masked bits.
+	var got []string
+	r.Root().WalkMask([]byte("t000user0001"), []byte("\xff\xff\xff\xfc\xff\xff\xff\xff\xff\xff\xff\xff"), func(k []byte, _ any) bool {
+		got = append(got, string(k))
+		return false
+	})
+	if !slices.Equal(got, []string{"t001user0001", "t002user0001"}) {
+		t.Fatalf("bad: %v", got)
+	}
+}
+
+func TestMaskIterator(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a1x", "a2x", "a2y", "b1x", "b2x", "a", "a1xx"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	it := r.Root().MaskIterator([]byte("?1x"), []byte{0, 0xff, 0xff})
+	var got []string
+	for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+		got = append(got, string(k))
+	}
+	if want := []string{"a1x", "a1xx", "b1x"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	defer func() {
+		if r := recover(); r != "iradix: pattern and mask lengths differ" {
+			t.Fatalf("bad panic: %v", r)
+		}
+	}()
+	r.Root().MaskIterator([]byte("a"), nil)
+}
@@ -0,0 +1,84 @@
+package iradix
+
+import "sync"
+
+// Memo caches the results of an expensive computation over the keys under a
+// prefix, such as a configuration rendered from a namespace, across
+// successive roots of a tree. A result is reused for as long as the subtree
+// under its prefix is unchanged, which the generation of the subtree tells
+// cheaply, see Node.Generation, and computed again once a root no longer
+// shares that subtree. Only committed trees should be used with it. A Memo is
+// safe for concurrent use.
+type Memo[T, R any] struct {
+	fn func(prefix []byte, n *Node[T]) R
+
+	l       sync.Mutex
+	entries map[string]memoEntry[R]
+}
+
+// memoEntry is a cached result and the generation of the subtree it was
+// computed from, zero if there were no keys.
+type memoEntry[R any] struct {
+	generation uint64
+	result     R
+}
+
+// NewMemo returns an empty Memo computing its results with fn, which is given
+// the prefix and the node holding the keys under it, or nil if there are
+// none.
+func NewMemo[T, R any](fn func(prefix []byte, n *Node[T]) R) *Memo[T, R] {
+	return &Memo[T, R]{
+		fn:      fn,
+		entries: make(map[string]memoEntry[R]),
+	}
+}
+
+// Get returns the result for the keys under the prefix in the tree rooted at
+// root, computing it only if the subtree changed since it was last computed.
+func (m *Memo[T, R]) Get(root *Node[T], prefix []byte) R {
+	n := root.prefixNode(prefix)
+	var gen uint64
+	if n != nil {
+		gen = n.generation
+	}
+
+	m.l.Lock()
+	e, ok := m.entries[string(prefix)]
+	m.l.Unlock()
+	if ok && e.generation == gen {
+		return e.result
+	}
+
+	r := m.fn(prefix, n)
+	m.l.Lock()
+	m.entries[string(prefix)] = memoEntry[R]{generation: gen, result: r}
+	m.l.Unlock()
+	return r
+}
+
+// Prune drops the cached results whose subtree isn't shared by the tree
+// rooted at root, so that results for prefixes that are no longer read don't
+// accumulate. It returns the number of results dropped.
+func (m *Memo[T, R]) Prune(root *Node[T]) int {
+	m.l.Lock()
+	defer m.l.Unlock()
+	dropped := 0
+	for p, e := range m.entries {
+		var gen uint64
+		if n := root.prefixNode([]byte(p)); n != nil {
+			gen = n.generation
+		}
+		if gen != e.generation {
+			delete(m.entries, p)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Len returns the number of cached results.
+func (m *Memo[T, R]) Len() int {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return len(m.entries)
+}
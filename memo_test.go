@@ -0,0 +1,60 @@
+package iradix
+
+import "testing"
+
+func TestMemo(t *testing.T) {
+	calls := 0
+	sum := NewMemo(func(_ []byte, n *Node[int]) int {
+		calls++
+		total := 0
+		if n != nil {
+			n.Walk(func(_ []byte, v int) bool {
+				total += v
+				return false
+			})
+		}
+		return total
+	})
+
+	txn := New[int]().Txn(false)
+	txn.Insert([]byte("a/1"), 1)
+	txn.Insert([]byte("a/2"), 2)
+	txn.Insert([]byte("b/1"), 10)
+	r := txn.Commit()
+
+	if got := sum.Get(r.Root(), []byte("a/")); got != 3 || calls != 1 {
+		t.Fatalf("bad: %d %d", got, calls)
+	}
+	if got := sum.Get(r.Root(), []byte("a/")); got != 3 || calls != 1 {
+		t.Fatalf("should be cached: %d %d", got, calls)
+	}
+
+	// Changes elsewhere keep the result.
+	r, _, _ = r.Insert([]byte("b/2"), 20)
+	if got := sum.Get(r.Root(), []byte("a/")); got != 3 || calls != 1 {
+		t.Fatalf("should be cached: %d %d", got, calls)
+	}
+	if got := sum.Get(r.Root(), []byte("b/")); got != 30 || calls != 2 {
+		t.Fatalf("bad: %d %d", got, calls)
+	}
+
+	// Changes under the prefix invalidate it.
+	r, _, _ = r.Insert([]byte("a/3"), 3)
+	if got := sum.Get(r.Root(), []byte("a/")); got != 6 || calls != 3 {
+		t.Fatalf("bad: %d %d", got, calls)
+	}
+
+	// Missing prefixes are cached until keys show up.
+	if got := sum.Get(r.Root(), []byte("c/")); got != 0 || calls != 4 {
+		t.Fatalf("bad: %d %d", got, calls)
+	}
+	sum.Get(r.Root(), []byte("c/"))
+	if calls != 4 {
+		t.Fatalf("should be cached: %d", calls)
+	}
+
+	r2, _ := r.DeletePrefix([]byte("b/"))
+	if dropped := sum.Prune(r2.Root()); dropped != 1 || sum.Len() != 2 {
+		t.Fatalf("bad: %d %d", dropped, sum.Len())
+	}
+}
@@ -0,0 +1,62 @@
+package iradix
+
+import "bytes"
+
+// MergeIterator iterates over the union of the keys of several trees in key
+// order, as if they were layered on top of each other. A key present in more
+// than one tree is returned once, with the value of the tree with the lowest
+// source index, so the trees should be given from the highest priority layer
+// to the lowest.
+type MergeIterator[T any] struct {
+	sources []mergeSource[T]
+}
+
+// mergeSource is the state of one of the trees of a MergeIterator.
+type mergeSource[T any] struct {
+	it  *Iterator[T]
+	key []byte
+	val T
+	ok  bool
+}
+
+// NewMergeIterator returns an iterator merging the trees rooted at the given
+// nodes. Nil roots are treated as empty trees.
+func NewMergeIterator[T any](roots ...*Node[T]) *MergeIterator[T] {
+	m := &MergeIterator[T]{sources: make([]mergeSource[T], len(roots))}
+	for i, root := range roots {
+		if root == nil {
+			continue
+		}
+		src := &m.sources[i]
+		src.it = root.Iterator()
+		src.key, src.val, src.ok = src.it.Next()
+	}
+	return m
+}
+
+// Next returns the next key, its value and the index of the tree it was taken
+// from, and false once the iteration is done.
+func (m *MergeIterator[T]) Next() ([]byte, T, int, bool) {
+	// Find the smallest key, preferring the lowest index on ties
+	best := -1
+	for i := range m.sources {
+		src := &m.sources[i]
+		if src.ok && (best < 0 || bytes.Compare(src.key, m.sources[best].key) < 0) {
+			best = i
+		}
+	}
+	if best < 0 {
+		var zero T
+		return nil, zero, -1, false
+	}
+	key, val := m.sources[best].key, m.sources[best].val
+
+	// Advance every source positioned at that key
+	for i := range m.sources {
+		src := &m.sources[i]
+		if src.ok && bytes.Equal(src.key, key) {
+			src.key, src.val, src.ok = src.it.Next()
+		}
+	}
+	return key, val, best, true
+}
@@ -0,0 +1,39 @@
+package iradix
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMergeIterator(t *testing.T) {
+	tree := func(kvs ...string) *Node[string] {
+		r := New[string]()
+		for i := 0; i < len(kvs); i += 2 {
+			r, _, _ = r.Insert([]byte(kvs[i]), kvs[i+1])
+		}
+		return r.Root()
+	}
+	overrides := tree("log.level", "debug")
+	env := tree("db.host", "prod-db", "log.level", "info")
+	defaults := tree("db.host", "localhost", "db.port", "5432", "log.level", "warn", "timeout", "30s")
+
+	it := NewMergeIterator(overrides, env, nil, defaults)
+	var got []string
+	for k, v, src, ok := it.Next(); ok; k, v, src, ok = it.Next() {
+		got = append(got, fmt.Sprintf("%s=%s@%d", k, v, src))
+	}
+	want := []string{
+		"db.host=prod-db@1",
+		"db.port=5432@3",
+		"log.level=debug@0",
+		"timeout=30s@3",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, _, _, ok := NewMergeIterator[string]().Next(); ok {
+		t.Fatalf("empty merge should be done")
+	}
+}
@@ -0,0 +1,51 @@
+package iradix
+
+// SetMeta attaches metadata to a key, such as flags or an owner ID, kept apart
+// from its value so that bookkeeping doesn't have to live in the value type.
+// The metadata stays attached when the value is updated and goes away with
+// the key. A nil meta removes it. It returns false, changing nothing, if the
+// key isn't in the tree or the change is rejected like a Delete would be, see
+// TryDelete. Metadata changes don't notify watchers.
+func (t *Txn[T]) SetMeta(k []byte, meta any) bool {
+	if _, ok := t.Get(k); !ok {
+		return false
+	}
+	if err := t.checkSealed(k); err != nil {
+		return false
+	}
+	if err := t.countMutation(); err != nil {
+		return false
+	}
+	if t.meta == nil {
+		t.meta = New[any]().Txn(false)
+	}
+	if meta == nil {
+		t.meta.Delete(k)
+	} else {
+		t.meta.Insert(k, meta)
+	}
+	t.changed = true
+	return true
+}
+
+// GetWithMeta is used to lookup a specific key, returning the value, its
+// metadata if any, see SetMeta, and if it was found
+func (t *Txn[T]) GetWithMeta(k []byte) (T, any, bool) {
+	v, ok := t.Get(k)
+	if !ok || t.meta == nil {
+		return v, nil, ok
+	}
+	meta, _ := t.meta.Get(k)
+	return v, meta, true
+}
+
+// GetWithMeta is used to lookup a specific key, returning the value, its
+// metadata if any, see Txn.SetMeta, and if it was found
+func (t *Tree[T]) GetWithMeta(k []byte) (T, any, bool) {
+	v, ok := t.Get(k)
+	if !ok || t.meta == nil {
+		return v, nil, ok
+	}
+	meta, _ := t.meta.Get(k)
+	return v, meta, true
+}
@@ -0,0 +1,54 @@
+package iradix
+
+import "testing"
+
+func TestMeta(t *testing.T) {
+	type owner struct{ id int }
+	r := New[string](WithSuffixIndex[string]())
+	r, _, _ = r.Insert([]byte("a/1"), "one")
+	r, _, _ = r.Insert([]byte("a/2"), "two")
+	r, _, _ = r.Insert([]byte("b"), "bee")
+
+	txn := r.Txn(false)
+	if txn.SetMeta([]byte("missing"), owner{1}) {
+		t.Fatalf("key doesn't exist")
+	}
+	for _, k := range []string{"a/1", "a/2", "b"} {
+		if !txn.SetMeta([]byte(k), owner{len(k)}) {
+			t.Fatalf("should set %q", k)
+		}
+	}
+	if v, meta, ok := txn.GetWithMeta([]byte("b")); !ok || v != "bee" || meta != (owner{1}) {
+		t.Fatalf("bad: %v %v %v", v, meta, ok)
+	}
+	r2 := txn.Commit()
+	if _, meta, _ := r.GetWithMeta([]byte("b")); meta != nil {
+		t.Fatalf("old tree changed: %v", meta)
+	}
+
+	// Updates keep the metadata, deletes drop it.
+	txn = r2.Txn(false)
+	txn.Insert([]byte("a/1"), "uno")
+	txn.DeletePrefix([]byte("a/2"))
+	txn.Delete([]byte("b"))
+	txn.Insert([]byte("a/2"), "dos")
+	txn.Insert([]byte("b"), "bee")
+	r3 := txn.Commit()
+	if v, meta, _ := r3.GetWithMeta([]byte("a/1")); v != "uno" || meta != (owner{3}) {
+		t.Fatalf("bad: %v %v", v, meta)
+	}
+	for _, k := range []string{"a/2", "b"} {
+		if _, meta, ok := r3.GetWithMeta([]byte(k)); !ok || meta != nil {
+			t.Fatalf("%q: metadata should be gone: %v", k, meta)
+		}
+	}
+
+	txn = r3.Txn(false)
+	txn.SetMeta([]byte("a/1"), nil)
+	if _, meta, _ := txn.GetWithMeta([]byte("a/1")); meta != nil {
+		t.Fatalf("should be cleared: %v", meta)
+	}
+	if _, _, ok := r3.GetWithMeta([]byte("zzz")); ok {
+		t.Fatalf("should be missing")
+	}
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+
+	iradix "github.com/absolutelightning/go-immutable-radix"
+)
+
+// OpKind is the kind of an operation run by ApplyOps.
+type OpKind int
+
+const (
+	// OpInsert inserts Value under Key.
+	OpInsert OpKind = iota
+
+	// OpDelete deletes Key.
+	OpDelete
+
+	// OpDeletePrefix deletes the keys under Key.
+	OpDeletePrefix
+
+	// OpGet looks Key up.
+	OpGet
+
+	// OpLongestPrefix looks up the longest prefix of Key.
+	OpLongestPrefix
+
+	// OpWalkPrefix walks the keys under Key.
+	OpWalkPrefix
+
+	// OpWalkPath walks the keys that are prefixes of Key.
+	OpWalkPath
+
+	// OpLowerBound iterates over the keys greater or equal to Key.
+	OpLowerBound
+
+	// numOpKinds is the number of kinds of operations.
+	numOpKinds
+)
+
+// String returns the name of the kind of operation.
+func (k OpKind) String() string {
+	switch k {
+	case OpInsert:
+		return "Insert"
+	case OpDelete:
+		return "Delete"
+	case OpDeletePrefix:
+		return "DeletePrefix"
+	case OpGet:
+		return "Get"
+	case OpLongestPrefix:
+		return "LongestPrefix"
+	case OpWalkPrefix:
+		return "WalkPrefix"
+	case OpWalkPath:
+		return "WalkPath"
+	case OpLowerBound:
+		return "LowerBound"
+	}
+	return fmt.Sprintf("OpKind(%d)", int(k))
+}
+
+// Op is an operation run by ApplyOps. Value is only used by OpInsert.
+type Op[T any] struct {
+	Kind  OpKind
+	Key   []byte
+	Value T
+}
+
+// String returns a description of the operation.
+func (op Op[T]) String() string {
+	if op.Kind == OpInsert {
+		return fmt.Sprintf("%v(%q, %v)", op.Kind, op.Key, op.Value)
+	}
+	return fmt.Sprintf("%v(%q)", op.Kind, op.Key)
+}
+
+// MismatchError is returned by ApplyOps when the tree and the model disagree.
+type MismatchError struct {
+	// Index is the index of the operation in the list, or -1 if the
+	// difference was found when comparing the final contents.
+	Index int
+	Op    string
+
+	// Tree and Model are the results of the operation, or the contents,
+	// according to each implementation.
+	Tree, Model any
+}
+
+// Error implements error.
+func (e *MismatchError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("contents differ: tree %v, model %v", e.Tree, e.Model)
+	}
+	return fmt.Sprintf("op %d %s: tree returned %v, model returned %v", e.Index, e.Op, e.Tree, e.Model)
+}
+
+// result is the outcome of an operation, as compared between implementations.
+type result struct {
+	Key   string
+	Value any
+	OK    bool
+	Walk  []string
+}
+
+// ApplyOps runs the operations on the given tree and on a model holding the
+// same contents, comparing every result, then compares the final contents.
+// It returns the resulting tree, and a *MismatchError describing the first
+// difference if there was one. Values are compared with reflect.DeepEqual.
+// Since the tree may be created with any options, this checks that a
+// configuration or an extension doesn't change the observable behaviour.
+func ApplyOps[T any](tree *iradix.Tree[T], ops []Op[T]) (*iradix.Tree[T], error) {
+	m := New[T]()
+	tree.Root().Walk(func(k []byte, v T) bool {
+		m, _, _ = m.Insert(k, v)
+		return false
+	})
+
+	for i, op := range ops {
+		var got, want result
+		switch op.Kind {
+		case OpInsert:
+			var old T
+			tree, old, got.OK = tree.Insert(op.Key, op.Value)
+			got.Value = old
+			m, old, want.OK = m.Insert(op.Key, op.Value)
+			want.Value = old
+		case OpDelete:
+			var old T
+			tree, old, got.OK = tree.Delete(op.Key)
+			got.Value = old
+			m, old, want.OK = m.Delete(op.Key)
+			want.Value = old
+		case OpDeletePrefix:
+			tree, got.OK = tree.DeletePrefix(op.Key)
+			m, want.OK = m.DeletePrefix(op.Key)
+		case OpGet:
+			var v T
+			v, got.OK = tree.Get(op.Key)
+			got.Value = v
+			v, want.OK = m.Get(op.Key)
+			want.Value = v
+		case OpLongestPrefix:
+			got = lookup(tree.Root().LongestPrefix(op.Key))
+			want = lookup(m.LongestPrefix(op.Key))
+		case OpWalkPrefix:
+			got.Walk = walk(func(fn func([]byte, T) bool) { tree.Root().WalkPrefix(op.Key, fn) })
+			want.Walk = walk(func(fn func([]byte, T) bool) { m.WalkPrefix(op.Key, fn) })
+		case OpWalkPath:
+			got.Walk = walk(func(fn func([]byte, T) bool) { tree.Root().WalkPath(op.Key, fn) })
+			want.Walk = walk(func(fn func([]byte, T) bool) { m.WalkPath(op.Key, fn) })
+		case OpLowerBound:
+			got.Walk = walk(func(fn func([]byte, T) bool) {
+				it := tree.Root().Iterator()
+				it.SeekLowerBound(op.Key)
+				for k, v, ok := it.Next(); ok; k, v, ok = it.Next() {
+					if fn(k, v) {
+						return
+					}
+				}
+			})
+			want.Walk = walk(func(fn func([]byte, T) bool) { m.LowerBound(op.Key, fn) })
+		default:
+			return tree, fmt.Errorf("op %d: unknown kind %v", i, op.Kind)
+		}
+		if !reflect.DeepEqual(got, want) {
+			return tree, &MismatchError{Index: i, Op: op.String(), Tree: got, Model: want}
+		}
+		if tree.Len() != m.Len() {
+			return tree, &MismatchError{Index: i, Op: op.String() + " then Len", Tree: tree.Len(), Model: m.Len()}
+		}
+	}
+
+	got := walk(func(fn func([]byte, T) bool) { tree.Root().Walk(fn) })
+	want := walk(m.Walk)
+	if !reflect.DeepEqual(got, want) {
+		return tree, &MismatchError{Index: -1, Tree: got, Model: want}
+	}
+	return tree, nil
+}
+
+// lookup returns the result of a lookup.
+func lookup[T any](k []byte, v T, ok bool) result {
+	return result{Key: string(k), Value: v, OK: ok}
+}
+
+// walk returns the keys and values visited by a walk, formatted.
+func walk[T any](w func(fn func([]byte, T) bool)) []string {
+	var out []string
+	w(func(k []byte, v T) bool {
+		out = append(out, fmt.Sprintf("%q=%v", k, v))
+		return false
+	})
+	return out
+}
+
+// DecodeOps turns arbitrary bytes, such as the input of a fuzz target, into a
+// list of operations for ApplyOps. Keys are drawn from a small alphabet so
+// that they often share prefixes, which exercises the splits and merges of
+// the tree's nodes. The value of each insert is its index in the list.
+func DecodeOps(data []byte) []Op[int] {
+	const alphabet = "ab/"
+	var ops []Op[int]
+	for len(data) > 0 {
+		kind := OpKind(data[0] % byte(numOpKinds))
+		n := 0
+		if len(data) > 1 {
+			n = int(data[1] % 6)
+		}
+		data = data[min(len(data), 2):]
+		n = min(n, len(data))
+		key := make([]byte, n)
+		for i, b := range data[:n] {
+			key[i] = alphabet[int(b)%len(alphabet)]
+		}
+		data = data[n:]
+		ops = append(ops, Op[int]{Kind: kind, Key: key, Value: len(ops)})
+	}
+	return ops
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	iradix "github.com/absolutelightning/go-immutable-radix"
+)
+
+func TestApplyOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	data := make([]byte, 20000)
+	rng.Read(data)
+	ops := DecodeOps(data)
+
+	r := iradix.New[int]()
+	r, _, _ = r.Insert([]byte("a/b"), -1)
+	r, err := ApplyOps(r, ops)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Other configurations must behave the same.
+	r2 := iradix.New[int](iradix.WithInsertionOrder[int](), iradix.WithSuffixIndex[int]())
+	if _, err := ApplyOps(r2, ops); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if _, err := ApplyOps(r, []Op[int]{{Kind: numOpKinds}}); err == nil {
+		t.Fatalf("expected an error for an unknown op")
+	}
+}
+
+func TestMismatchError(t *testing.T) {
+	var err error = &MismatchError{Index: 3, Op: `Get("a")`, Tree: 1, Model: 2}
+	var m *MismatchError
+	if !errors.As(err, &m) || err.Error() != `op 3 Get("a"): tree returned 1, model returned 2` {
+		t.Fatalf("bad: %v", err)
+	}
+}
+
+func FuzzApplyOps(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 0, 1, 1, 1, 5, 0, 2, 0})
+	f.Add([]byte{0, 5, 0, 0, 0, 0, 0, 0, 0, 2, 0, 1, 2, 1, 0, 7, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if _, err := ApplyOps(iradix.New[int](), DecodeOps(data)); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+// Package model is a reference implementation of the iradix tree API over a
+// sorted slice of keys, written to be obviously correct rather than fast. It
+// serves as an oracle for differential testing: ApplyOps runs the same
+// operations against an iradix tree and the model and reports the first
+// difference, so downstream users can fuzz their own extensions and
+// configurations of the tree against it.
+package model
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Tree is an immutable sorted map from keys to values mirroring the API of
+// iradix.Tree. Every modification copies the whole map.
+type Tree[T any] struct {
+	keys []string
+	vals map[string]T
+}
+
+// New returns an empty Tree.
+func New[T any]() *Tree[T] {
+	return &Tree[T]{vals: make(map[string]T)}
+}
+
+// clone returns a copy of the tree that can be modified.
+func (t *Tree[T]) clone() *Tree[T] {
+	nt := &Tree[T]{
+		keys: append([]string(nil), t.keys...),
+		vals: make(map[string]T, len(t.vals)),
+	}
+	for k, v := range t.vals {
+		nt.vals[k] = v
+	}
+	return nt
+}
+
+// search returns the index of the first key greater or equal to k.
+func (t *Tree[T]) search(k string) int {
+	return sort.SearchStrings(t.keys, k)
+}
+
+// Len is used to return the number of elements in the tree
+func (t *Tree[T]) Len() int {
+	return len(t.keys)
+}
+
+// Get is used to lookup a specific key, returning
+// the value and if it was found
+func (t *Tree[T]) Get(k []byte) (T, bool) {
+	v, ok := t.vals[string(k)]
+	return v, ok
+}
+
+// Insert is used to add or update a given key. The return provides
+// the new tree, previous value and a bool indicating if any was set.
+func (t *Tree[T]) Insert(k []byte, v T) (*Tree[T], T, bool) {
+	nt := t.clone()
+	old, ok := nt.vals[string(k)]
+	if !ok {
+		i := nt.search(string(k))
+		nt.keys = slices.Insert(nt.keys, i, string(k))
+	}
+	nt.vals[string(k)] = v
+	return nt, old, ok
+}
+
+// Delete is used to delete a given key. Returns the new tree,
+// old value if any, and a bool indicating if the key was set.
+func (t *Tree[T]) Delete(k []byte) (*Tree[T], T, bool) {
+	old, ok := t.vals[string(k)]
+	if !ok {
+		return t, old, false
+	}
+	nt := t.clone()
+	i := nt.search(string(k))
+	nt.keys = slices.Delete(nt.keys, i, i+1)
+	delete(nt.vals, string(k))
+	return nt, old, true
+}
+
+// DeletePrefix is used to delete all nodes starting with a given prefix.
+// Returns the new tree, and a bool indicating if the prefix matched any
+// nodes. Like iradix, the empty prefix always matches the root, even in an
+// empty tree.
+func (t *Tree[T]) DeletePrefix(prefix []byte) (*Tree[T], bool) {
+	i, j := t.prefixRange(string(prefix))
+	if i == j {
+		return t, len(prefix) == 0
+	}
+	nt := t.clone()
+	for _, k := range nt.keys[i:j] {
+		delete(nt.vals, k)
+	}
+	nt.keys = slices.Delete(nt.keys, i, j)
+	return nt, true
+}
+
+// prefixRange returns the range of indexes of the keys under a prefix.
+func (t *Tree[T]) prefixRange(prefix string) (int, int) {
+	i := t.search(prefix)
+	j := i
+	for j < len(t.keys) && strings.HasPrefix(t.keys[j], prefix) {
+		j++
+	}
+	return i, j
+}
+
+// LongestPrefix is like Get, but instead of an exact match, it will return
+// the longest prefix match.
+func (t *Tree[T]) LongestPrefix(k []byte) ([]byte, T, bool) {
+	for i := len(k); i >= 0; i-- {
+		if v, ok := t.vals[string(k[:i])]; ok {
+			return k[:i:i], v, true
+		}
+	}
+	var zero T
+	return nil, zero, false
+}
+
+// Minimum is used to return the minimum value in the tree
+func (t *Tree[T]) Minimum() ([]byte, T, bool) {
+	if len(t.keys) == 0 {
+		var zero T
+		return nil, zero, false
+	}
+	k := t.keys[0]
+	return []byte(k), t.vals[k], true
+}
+
+// Maximum is used to return the maximum value in the tree
+func (t *Tree[T]) Maximum() ([]byte, T, bool) {
+	if len(t.keys) == 0 {
+		var zero T
+		return nil, zero, false
+	}
+	k := t.keys[len(t.keys)-1]
+	return []byte(k), t.vals[k], true
+}
+
+// Walk is used to walk the tree in order. Returning true from fn stops the
+// walk.
+func (t *Tree[T]) Walk(fn func(k []byte, v T) bool) {
+	t.WalkPrefix(nil, fn)
+}
+
+// WalkPrefix is used to walk the keys under a prefix in order
+func (t *Tree[T]) WalkPrefix(prefix []byte, fn func(k []byte, v T) bool) {
+	i, j := t.prefixRange(string(prefix))
+	for _, k := range t.keys[i:j] {
+		if fn([]byte(k), t.vals[k]) {
+			return
+		}
+	}
+}
+
+// WalkPath is used to walk the keys that are prefixes of the given path,
+// from the shortest to the longest
+func (t *Tree[T]) WalkPath(path []byte, fn func(k []byte, v T) bool) {
+	for i := 0; i <= len(path); i++ {
+		if v, ok := t.vals[string(path[:i])]; ok && fn(path[:i:i], v) {
+			return
+		}
+	}
+}
+
+// LowerBound is used to walk the keys greater or equal to the given key in
+// order, like an iterator after SeekLowerBound
+func (t *Tree[T]) LowerBound(k []byte, fn func(k []byte, v T) bool) {
+	for _, key := range t.keys[t.search(string(k)):] {
+		if fn([]byte(key), t.vals[key]) {
+			return
+		}
+	}
+}
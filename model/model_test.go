@@ -0,0 +1,60 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"foo", "foo/bar", "foobar", "zip", ""} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	r2, old, ok := r.Insert([]byte("foo"), 10)
+	if !ok || old != 0 || r2.Len() != 5 {
+		t.Fatalf("bad: %v %v %d", old, ok, r2.Len())
+	}
+	if v, _ := r.Get([]byte("foo")); v != 0 {
+		t.Fatalf("old tree changed: %d", v)
+	}
+
+	var keys []string
+	r.Walk(func(k []byte, _ int) bool {
+		keys = append(keys, string(k))
+		return false
+	})
+	if want := []string{"", "foo", "foo/bar", "foobar", "zip"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("got %v want %v", keys, want)
+	}
+
+	if k, v, ok := r.LongestPrefix([]byte("foo/baz")); string(k) != "foo" || v != 0 || !ok {
+		t.Fatalf("bad: %q %d %v", k, v, ok)
+	}
+	if k, _, _ := r.Minimum(); string(k) != "" {
+		t.Fatalf("bad min: %q", k)
+	}
+	if k, _, _ := r.Maximum(); string(k) != "zip" {
+		t.Fatalf("bad max: %q", k)
+	}
+
+	r3, ok := r.DeletePrefix([]byte("foo"))
+	if !ok || r3.Len() != 2 {
+		t.Fatalf("bad: %v %d", ok, r3.Len())
+	}
+	if _, ok := r3.DeletePrefix([]byte("foo")); ok {
+		t.Fatalf("nothing left to delete")
+	}
+	r4, old, ok := r3.Delete([]byte("zip"))
+	if !ok || old != 3 || r4.Len() != 1 {
+		t.Fatalf("bad: %v %v %d", old, ok, r4.Len())
+	}
+
+	keys = nil
+	r.WalkPath([]byte("foo/bar/baz"), func(k []byte, _ int) bool {
+		keys = append(keys, string(k))
+		return false
+	})
+	if want := []string{"", "foo", "foo/bar"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("got %v want %v", keys, want)
+	}
+}
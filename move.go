@@ -0,0 +1,86 @@
+package iradix
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// KeyExistsError is returned by TryMove when the destination key already
+// exists and overwriting it wasn't allowed.
+type KeyExistsError struct {
+	Key []byte
+}
+
+func (e *KeyExistsError) Error() string {
+	return fmt.Sprintf("key %q already exists", e.Key)
+}
+
+// Move is used to move the value of oldKey to newKey, deleting oldKey, as a
+// single mutation of the transaction: readers of the committed tree either
+// see the value under oldKey or under newKey. It returns true if the value
+// was moved. Moves onto an existing key without overwrite, and moves rejected
+// like Insert and Delete are, are not applied, see TryMove.
+func (t *Txn[T]) Move(oldKey, newKey []byte, overwrite bool) bool {
+	moved, _ := t.TryMove(oldKey, newKey, overwrite)
+	return moved
+}
+
+// TryMove is like Move, but returns a *KeyExistsError if newKey exists and
+// overwrite is false, or the error that TryDelete or TryInsert would return,
+// in which case nothing is changed. The error is also recorded for Err.
+// Moving a missing key, or a key onto itself, does nothing and isn't an
+// error, but like TryDelete it fails if oldKey is sealed. The metadata of
+// oldKey, see SetMeta, moves along with its value. The move counts as two
+// mutations, see WithMaxTxnMutations.
+//
+// The value is taken from the leaf deleted for oldKey, so a move searches
+// the tree once to delete oldKey and once to insert newKey. Only a move
+// without overwrite looks newKey up before that.
+func (t *Txn[T]) TryMove(oldKey, newKey []byte, overwrite bool) (bool, error) {
+	if err := t.checkSealed(oldKey); err != nil {
+		return false, err
+	}
+	if bytes.Equal(oldKey, newKey) {
+		_, ok := t.Get(oldKey)
+		return ok, nil
+	}
+
+	// Check everything that could reject either half before deleting
+	// oldKey, so that the move is either applied entirely or not at all.
+	if err := t.conf.validateKey(newKey); err != nil {
+		return false, t.reject(err)
+	}
+	if err := t.checkSealed(newKey); err != nil {
+		return false, err
+	}
+	if t.conf != nil && t.conf.maxTxnMutations > 0 && t.mutations+2 > t.conf.maxTxnMutations {
+		return false, t.reject(ErrMaxTxnMutations)
+	}
+	if !overwrite && t.root.leafFor(newKey) != nil {
+		if t.root.leafFor(oldKey) == nil {
+			return false, nil
+		}
+		return false, t.reject(&KeyExistsError{Key: newKey})
+	}
+
+	// The metadata goes away with oldKey, and newKey takes the one of
+	// oldKey in place of its own.
+	var meta any
+	if t.meta != nil {
+		meta, _ = t.meta.Get(oldKey)
+	}
+	leaf := t.deleteLeaf(oldKey)
+	if leaf == nil {
+		return false, nil
+	}
+	t.addMutations(1)
+	if _, _, err := t.TryInsert(newKey, leaf.val); err != nil {
+		return false, err
+	}
+	if meta != nil {
+		t.meta.Insert(newKey, meta)
+	} else if t.meta != nil {
+		t.meta.Delete(newKey)
+	}
+	return true, nil
+}
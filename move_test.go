@@ -0,0 +1,121 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	r := New[int](WithMaxTxnMutations[int](5))
+	r, _, _ = r.Insert([]byte("a"), 1)
+	r, _, _ = r.Insert([]byte("b"), 2)
+
+	txn := r.Txn(false)
+	if !txn.Move([]byte("a"), []byte("c"), false) {
+		t.Fatalf("should move")
+	}
+	if txn.Move([]byte("missing"), []byte("d"), false) || txn.Err() != nil {
+		t.Fatalf("nothing to move: %v", txn.Err())
+	}
+
+	moved, err := txn.TryMove([]byte("c"), []byte("b"), false)
+	var exists *KeyExistsError
+	if moved || !errors.As(err, &exists) || string(exists.Key) != "b" {
+		t.Fatalf("bad: %v %v", moved, err)
+	}
+	if !errors.As(txn.Err(), &exists) {
+		t.Fatalf("error should be recorded: %v", txn.Err())
+	}
+	if !txn.Move([]byte("c"), []byte("b"), true) {
+		t.Fatalf("should overwrite")
+	}
+	if !txn.Move([]byte("b"), []byte("b"), false) {
+		t.Fatalf("moving onto itself is a no-op")
+	}
+	r2 := txn.Commit()
+	if r2.Len() != 1 {
+		t.Fatalf("bad len: %d", r2.Len())
+	}
+	if v, _ := r2.Get([]byte("b")); v != 1 {
+		t.Fatalf("bad value: %d", v)
+	}
+
+	// Moves are applied entirely or not at all.
+	txn = r.Txn(false)
+	txn.Insert([]byte("x"), 3)
+	txn.Insert([]byte("y"), 4)
+	txn.Insert([]byte("z"), 5)
+	txn.Insert([]byte("w"), 6)
+	if _, err := txn.TryMove([]byte("a"), []byte("e"), false); !errors.Is(err, ErrMaxTxnMutations) {
+		t.Fatalf("expected ErrMaxTxnMutations, got %v", err)
+	}
+	if _, ok := txn.Get([]byte("a")); !ok {
+		t.Fatalf("a should still be there")
+	}
+
+	// Moving a missing key doesn't count against the limit.
+	txn = r.Txn(false)
+	for i := 0; i < 10; i++ {
+		if moved, err := txn.TryMove([]byte("missing"), []byte("e"), true); moved || err != nil {
+			t.Fatalf("bad: %v %v", moved, err)
+		}
+	}
+	if !txn.Move([]byte("a"), []byte("e"), false) {
+		t.Fatalf("should move")
+	}
+
+	txn = r.Txn(false)
+	txn.SealPrefix([]byte("s/"))
+	if _, err := txn.TryMove([]byte("a"), []byte("s/a"), false); !errors.Is(err, ErrSealed) {
+		t.Fatalf("expected ErrSealed, got %v", err)
+	}
+	if _, ok := txn.Get([]byte("a")); !ok {
+		t.Fatalf("a should still be there")
+	}
+
+	// A sealed key can't be moved, not even onto itself.
+	txn = r.Txn(false)
+	txn.SealPrefix([]byte("a"))
+	for _, dst := range []string{"a", "e"} {
+		if moved, err := txn.TryMove([]byte("a"), []byte(dst), false); moved || !errors.Is(err, ErrSealed) {
+			t.Fatalf("move to %q: expected ErrSealed, got %v %v", dst, moved, err)
+		}
+	}
+}
+
+func TestMove_Meta(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a", "b", "c"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	txn := r.Txn(false)
+	txn.SetMeta([]byte("a"), "owner-a")
+	txn.SetMeta([]byte("c"), "owner-c")
+
+	if !txn.Move([]byte("a"), []byte("d"), false) {
+		t.Fatalf("should move")
+	}
+	if _, meta, _ := txn.GetWithMeta([]byte("d")); meta != "owner-a" {
+		t.Fatalf("metadata should move: %v", meta)
+	}
+
+	// Overwriting takes the metadata of the key moved, or none.
+	if !txn.Move([]byte("d"), []byte("c"), true) {
+		t.Fatalf("should overwrite")
+	}
+	if _, meta, _ := txn.GetWithMeta([]byte("c")); meta != "owner-a" {
+		t.Fatalf("bad metadata: %v", meta)
+	}
+	if !txn.Move([]byte("b"), []byte("c"), true) {
+		t.Fatalf("should overwrite")
+	}
+	r = txn.Commit()
+	if _, meta, ok := r.GetWithMeta([]byte("c")); !ok || meta != nil {
+		t.Fatalf("bad metadata: %v %v", meta, ok)
+	}
+	for _, k := range []string{"a", "b", "d"} {
+		if _, meta, _ := r.GetWithMeta([]byte(k)); meta != nil {
+			t.Fatalf("%q should have no metadata: %v", k, meta)
+		}
+	}
+}
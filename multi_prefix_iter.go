@@ -0,0 +1,56 @@
+package iradix
+
+import (
+	"bytes"
+	"sort"
+)
+
+// MultiPrefixIterator iterates over the union of the keys under several
+// prefixes, in key order and without duplicates.
+type MultiPrefixIterator[T any] struct {
+	root     *Node[T]
+	prefixes [][]byte
+	cur      *Iterator[T]
+}
+
+// NewMultiPrefixIterator returns an iterator over the keys under any of the
+// given prefixes in the tree rooted at root.
+func NewMultiPrefixIterator[T any](root *Node[T], prefixes [][]byte) *MultiPrefixIterator[T] {
+	sorted := make([][]byte, len(prefixes))
+	copy(sorted, prefixes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	// Drop the prefixes covered by another one. A covering prefix sorts
+	// before everything it covers, so after this the remaining prefixes
+	// cover disjoint key ranges that are in the same order as the prefixes
+	// themselves, and can simply be scanned one after the other.
+	var disjoint [][]byte
+	for _, p := range sorted {
+		if n := len(disjoint); n > 0 && bytes.HasPrefix(p, disjoint[n-1]) {
+			continue
+		}
+		disjoint = append(disjoint, p)
+	}
+	return &MultiPrefixIterator[T]{root: root, prefixes: disjoint}
+}
+
+// Next returns the next key and value, and false once the iteration is done.
+func (m *MultiPrefixIterator[T]) Next() ([]byte, T, bool) {
+	for {
+		if m.cur != nil {
+			if k, v, ok := m.cur.Next(); ok {
+				return k, v, true
+			}
+			m.cur = nil
+		}
+		if len(m.prefixes) == 0 {
+			var zero T
+			return nil, zero, false
+		}
+		m.cur = m.root.Iterator()
+		m.cur.SeekPrefix(m.prefixes[0])
+		m.prefixes = m.prefixes[1:]
+	}
+}
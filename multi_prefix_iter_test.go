@@ -0,0 +1,44 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMultiPrefixIterator(t *testing.T) {
+	r := New[any]()
+	keys := []string{"a/1", "a/2", "ab", "b/1", "c/1", "c/2", "d"}
+	for _, k := range keys {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+
+	cases := []struct {
+		name     string
+		prefixes []string
+		want     []string
+	}{
+		{"single", []string{"a/"}, []string{"a/1", "a/2"}},
+		{"unordered", []string{"c/", "a/"}, []string{"a/1", "a/2", "c/1", "c/2"}},
+		{"overlapping", []string{"a/", "a", "a/1"}, []string{"a/1", "a/2", "ab"}},
+		{"duplicates", []string{"d", "d", "b"}, []string{"b/1", "d"}},
+		{"missing", []string{"x", "b/1"}, []string{"b/1"}},
+		{"everything", []string{"c", ""}, keys},
+		{"none", nil, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var prefixes [][]byte
+			for _, p := range c.prefixes {
+				prefixes = append(prefixes, []byte(p))
+			}
+			it := NewMultiPrefixIterator(r.Root(), prefixes)
+			var got []string
+			for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+				got = append(got, string(k))
+			}
+			if !slices.Equal(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package iradix
+
+import "bytes"
+
+// WithNGramIndex makes the tree maintain a secondary index from the n-grams
+// of its keys, the substrings of n bytes, to the keys containing them. It is
+// kept in sync by the transactions and published with each commit, and lets
+// ContainsSubstring find keys by a substring without scanning the whole tree.
+// The index holds a key once per distinct n-gram, so small values of n such
+// as 3 keep it compact.
+func WithNGramIndex[T any](n int) Option[T] {
+	return func(c *config[T]) {
+		c.ngramSize = n
+	}
+}
+
+// ngramUpdate adds or removes the entries of the n-gram index for a key. The
+// entries are the n-gram followed by the key, so the keys containing an n-gram
+// are the ones under it.
+func (t *Txn[T]) ngramUpdate(k []byte, add bool) {
+	n := t.conf.ngramSize
+	for i := 0; i+n <= len(k); i++ {
+		entry := make([]byte, 0, n+len(k))
+		entry = append(append(entry, k[i:i+n]...), k...)
+		if add {
+			t.ngram.Insert(entry, struct{}{})
+		} else {
+			t.ngram.Delete(entry)
+		}
+	}
+}
+
+// ContainsSubstring is used to walk the keys of the tree containing the given
+// substring, in key order. With an n-gram index, see WithNGramIndex, only the
+// keys sharing the first n-gram of the substring are checked, otherwise and
+// for substrings shorter than n the whole tree is scanned.
+func (t *Tree[T]) ContainsSubstring(sub []byte, fn WalkFn[T]) {
+	if t.ngram == nil || len(sub) < t.conf.ngramSize {
+		t.root.Walk(func(k []byte, v T) bool {
+			if bytes.Contains(k, sub) {
+				return fn(k, v)
+			}
+			return false
+		})
+		return
+	}
+
+	n := t.conf.ngramSize
+	t.ngram.Root().WalkPrefix(sub[:n], func(entry []byte, _ struct{}) bool {
+		k := entry[n:]
+		if !bytes.Contains(k, sub) {
+			return false
+		}
+		v, _ := t.root.Get(k)
+		return fn(k, v)
+	})
+}
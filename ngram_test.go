@@ -0,0 +1,44 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContainsSubstring(t *testing.T) {
+	keys := []string{"user/alice", "user/bob", "team/alice-fans", "al", "malice"}
+	indexed := New[int](WithNGramIndex[int](3))
+	plain := New[int]()
+	for i, k := range keys {
+		indexed, _, _ = indexed.Insert([]byte(k), i)
+		plain, _, _ = plain.Insert([]byte(k), i)
+	}
+
+	find := func(r *Tree[int], sub string) []string {
+		var out []string
+		r.ContainsSubstring([]byte(sub), func(k []byte, v int) bool {
+			out = append(out, string(k))
+			return false
+		})
+		return out
+	}
+	for _, sub := range []string{"alice", "lic", "al", "bob", "zzz", "", "user/"} {
+		got, want := find(indexed, sub), find(plain, sub)
+		if !slices.Equal(got, want) {
+			t.Fatalf("%q: got %v, want %v", sub, got, want)
+		}
+	}
+	if got := find(indexed, "alice"); !slices.Equal(got, []string{"malice", "team/alice-fans", "user/alice"}) {
+		t.Fatalf("bad: %v", got)
+	}
+
+	// The index follows deletes.
+	indexed, _, _ = indexed.Delete([]byte("malice"))
+	indexed, _ = indexed.DeletePrefix([]byte("team/"))
+	if got := find(indexed, "alice"); !slices.Equal(got, []string{"user/alice"}) {
+		t.Fatalf("bad: %v", got)
+	}
+	if indexed.ngram.Len() != len("user/alice")-2+len("user/bob")-2 {
+		t.Fatalf("stale index entries: %d", indexed.ngram.Len())
+	}
+}
@@ -17,6 +17,10 @@ type leafNode[T any] struct {
 	key      []byte
 	val      T
 	refCount int64
+
+	// extra holds the state only some of the tree's options need, see
+	// leafExtra. It is nil for leaves of trees that use none of them.
+	extra *leafExtra
 }
 
 // edge is used to represent an edge node
@@ -27,6 +31,10 @@ type edge[T any] struct {
 
 // Node is an immutable node in the radix tree
 type Node[T any] struct {
+	// audit is only populated in builds with the iradix_audit tag. It is
+	// first so that it doesn't add any padding when it is empty.
+	audit nodeAudit
+
 	refCount     int64
 	lazyRefCount int64
 
@@ -43,6 +51,35 @@ type Node[T any] struct {
 	// We avoid a fully materialized slice to save memory,
 	// since in most cases we expect to be sparse
 	edges edges[T]
+
+	// settled is set once the state derived from the subtree below (see
+	// settle) is up to date. New and modified nodes are unsettled until the
+	// transaction that created them is committed.
+	settled bool
+
+	// noWatch and noLeafWatch are set on settled nodes that don't allocate
+	// a watch channel for themselves or for their leaf, see
+	// WithWatchAllocation.
+	noWatch     bool
+	noLeafWatch bool
+
+	// agg holds the state derived from the subtree rooted at this node for
+	// the options that need it, see nodeAggregates. It is nil for nodes of
+	// trees that use none of them, and for nodes modified by a transaction
+	// until it is committed.
+	agg *nodeAggregates
+
+	// leaves is the number of leaves in the subtree rooted at this node. It
+	// is only valid on settled nodes.
+	leaves int
+
+	// generation is stamped on the node when it is settled, see Generation.
+	generation uint64
+
+	// labels is the set of edge labels of a settled node with many edges,
+	// see labelBitmap. It is nil otherwise, in which case the edges are
+	// searched instead.
+	labels *labelBitmap
 }
 
 func (n *Node[T]) isLeaf() bool {
@@ -74,6 +111,13 @@ func (n *Node[T]) replaceEdge(e edge[T]) {
 }
 
 func (n *Node[T]) getEdge(label byte) (int, *Node[T]) {
+	if n.labels != nil {
+		if !n.labels.has(label) {
+			return -1, nil
+		}
+		idx := n.labels.rank(label)
+		return idx, n.edges[idx].node
+	}
 	num := len(n.edges)
 	idx := sort.Search(num, func(i int) bool {
 		return n.edges[i].label >= label
@@ -85,6 +129,12 @@ func (n *Node[T]) getEdge(label byte) (int, *Node[T]) {
 }
 
 func (n *Node[T]) getLowerBoundEdge(label byte) (int, *Node[T]) {
+	if n.labels != nil {
+		if idx := n.labels.rank(label); idx < len(n.edges) {
+			return idx, n.edges[idx].node
+		}
+		return -1, nil
+	}
 	num := len(n.edges)
 	idx := sort.Search(num, func(i int) bool {
 		return n.edges[i].label >= label
@@ -110,12 +160,13 @@ func (n *Node[T]) delEdge(label byte) {
 
 func (n *Node[T]) GetWatch(k []byte) (<-chan struct{}, T, bool) {
 	search := k
-	watch := n.getMutateCh()
+	var watch <-chan struct{} = n.getMutateCh()
+	n.auditCheck()
 	for {
 		// Check for key exhaustion
 		if len(search) == 0 {
 			if n.isLeaf() {
-				return n.leaf.getMutateCh(), n.leaf.val, true
+				return n.leafWatchCh(watch), n.leaf.val, true
 			}
 			break
 		}
@@ -125,9 +176,10 @@ func (n *Node[T]) GetWatch(k []byte) (<-chan struct{}, T, bool) {
 		if n == nil {
 			break
 		}
+		n.auditCheck()
 
 		// Update to the finest granularity as the search makes progress
-		watch = n.getMutateCh()
+		watch = n.watchCh(watch)
 
 		// Consume the search prefix
 		if bytes.HasPrefix(search, n.prefix) {
@@ -148,12 +200,39 @@ func (n *Node[T]) Get(k []byte) (T, bool) {
 // LongestPrefix is like Get, but instead of an
 // exact match, it will return the longest prefix match.
 func (n *Node[T]) LongestPrefix(k []byte) ([]byte, T, bool) {
-	var last *leafNode[T]
+	if match := n.longestPrefixNode(k); match != nil {
+		return match.leaf.key, match.leaf.val, true
+	}
+	var zero T
+	return nil, zero, false
+}
+
+// LongestPrefixWatch is like LongestPrefix, but also returns a watch channel
+// that fires if the result for k may have changed: the matched key being
+// updated or deleted, or a longer prefix of k being inserted. This is the
+// channel of the matched node, or of n if there is no match.
+func (n *Node[T]) LongestPrefixWatch(k []byte) (<-chan struct{}, []byte, T, bool) {
+	if match := n.longestPrefixNode(k); match != nil {
+		watch := match.watchCh(nil)
+		if watch == nil {
+			watch = n.getMutateCh()
+		}
+		return watch, match.leaf.key, match.leaf.val, true
+	}
+	var zero T
+	return n.getMutateCh(), nil, zero, false
+}
+
+// longestPrefixNode returns the deepest node with a leaf whose key is a
+// prefix of k, or nil if there is none.
+func (n *Node[T]) longestPrefixNode(k []byte) *Node[T] {
+	var last *Node[T]
 	search := k
+	n.auditCheck()
 	for {
 		// Look for a leaf node
 		if n.isLeaf() {
-			last = n.leaf
+			last = n
 		}
 
 		// Check for key exhaustion
@@ -166,6 +245,7 @@ func (n *Node[T]) LongestPrefix(k []byte) ([]byte, T, bool) {
 		if n == nil {
 			break
 		}
+		n.auditCheck()
 
 		// Consume the search prefix
 		if bytes.HasPrefix(search, n.prefix) {
@@ -174,11 +254,7 @@ func (n *Node[T]) LongestPrefix(k []byte) ([]byte, T, bool) {
 			break
 		}
 	}
-	if last != nil {
-		return last.key, last.val, true
-	}
-	var zero T
-	return nil, zero, false
+	return last
 }
 
 // Minimum is used to return the minimum value in the tree
@@ -214,12 +290,36 @@ func (n *Node[T]) Maximum() ([]byte, T, bool) {
 	return nil, zero, false
 }
 
+// MinimumWatch is like Minimum, but also returns a watch channel that fires
+// when the minimum may have changed. A smaller key can be inserted below any
+// node on the leftmost path, so this is the channel of n itself, which also
+// fires for changes that leave the minimum as it was.
+func (n *Node[T]) MinimumWatch() (<-chan struct{}, []byte, T, bool) {
+	k, v, ok := n.Minimum()
+	return n.getMutateCh(), k, v, ok
+}
+
+// MaximumWatch is like Maximum, but also returns a watch channel that fires
+// when the maximum may have changed, see MinimumWatch.
+func (n *Node[T]) MaximumWatch() (<-chan struct{}, []byte, T, bool) {
+	k, v, ok := n.Maximum()
+	return n.getMutateCh(), k, v, ok
+}
+
 // Iterator is used to return an iterator at
 // the given node to walk the tree
 func (n *Node[T]) Iterator() *Iterator[T] {
 	return &Iterator[T]{node: n}
 }
 
+// Range is used to return an iterator over the keys k with
+// start <= k < end, see Iterator.SeekRange
+func (n *Node[T]) Range(start, end []byte) *Iterator[T] {
+	i := n.Iterator()
+	i.SeekRange(start, end)
+	return i
+}
+
 // ReverseIterator is used to return an iterator at
 // the given node to walk the tree backwards
 func (n *Node[T]) ReverseIterator() *ReverseIterator[T] {
@@ -280,6 +380,66 @@ func (n *Node[T]) WalkPrefix(prefix []byte, fn WalkFn[T]) {
 	}
 }
 
+// WalkPrefixWatch is used to walk the tree under a prefix, like WalkPrefix,
+// and returns the narrowest watch channel that fires on any change to the
+// keys under that prefix.
+func (n *Node[T]) WalkPrefixWatch(prefix []byte, fn WalkFn[T]) <-chan struct{} {
+	var watch <-chan struct{} = n.getMutateCh()
+	search := prefix
+	for {
+		// Check for key exhaustion
+		if len(search) == 0 {
+			recursiveWalk(n, fn)
+			return watch
+		}
+
+		// Look for an edge
+		_, n = n.getEdge(search[0])
+		if n == nil {
+			return watch
+		}
+
+		// Update to the finest granularity as the search makes progress
+		watch = n.watchCh(watch)
+
+		// Consume the search prefix
+		if bytes.HasPrefix(search, n.prefix) {
+			search = search[len(n.prefix):]
+
+		} else if bytes.HasPrefix(n.prefix, search) {
+			// Child may be under our search prefix
+			recursiveWalk(n, fn)
+			return watch
+		} else {
+			return watch
+		}
+	}
+}
+
+// prefixNode returns the highest node whose subtree holds exactly the keys
+// under the given prefix, or nil if there are none.
+func (n *Node[T]) prefixNode(prefix []byte) *Node[T] {
+	search := prefix
+	for len(search) > 0 {
+		// Look for an edge
+		_, n = n.getEdge(search[0])
+		if n == nil {
+			return nil
+		}
+
+		// Consume the search prefix
+		if bytes.HasPrefix(search, n.prefix) {
+			search = search[len(n.prefix):]
+		} else if bytes.HasPrefix(n.prefix, search) {
+			// Child may be under our search prefix
+			return n
+		} else {
+			return nil
+		}
+	}
+	return n
+}
+
 // WalkPath is used to walk the tree, but only visiting nodes
 // from the root down to a given leaf. Where WalkPrefix walks
 // all the entries *under* the given prefix, this walks the
@@ -294,9 +454,38 @@ func (n *Node[T]) WalkPath(path []byte, fn WalkFn[T]) {
 	}
 }
 
+// WalkPrefixBackwards is used to walk the tree under a prefix in reverse
+// order, like WalkBackwards
+func (n *Node[T]) WalkPrefixBackwards(prefix []byte, fn WalkFn[T]) {
+	if n = n.prefixNode(prefix); n != nil {
+		reverseRecursiveWalk(n, fn)
+	}
+}
+
+// WalkPathBackwards is used to walk the entries above the given
+// path like WalkPath, but from the deepest entry up to the root
+func (n *Node[T]) WalkPathBackwards(path []byte, fn WalkFn[T]) {
+	type entry struct {
+		key []byte
+		val T
+	}
+	var entries []entry
+	n.WalkPath(path, func(k []byte, v T) bool {
+		entries = append(entries, entry{k, v})
+		return false
+	})
+	for i := len(entries) - 1; i >= 0; i-- {
+		if fn(entries[i].key, entries[i].val) {
+			return
+		}
+	}
+}
+
 // recursiveWalk is used to do a pre-order walk of a node
 // recursively. Returns true if the walk should be aborted
 func recursiveWalk[T any](n *Node[T], fn WalkFn[T]) bool {
+	n.auditCheck()
+
 	// Visit the leaf values if any
 	if n.leaf != nil && fn(n.leaf.key, n.leaf.val) {
 		return true
@@ -315,6 +504,8 @@ func recursiveWalk[T any](n *Node[T], fn WalkFn[T]) bool {
 // walk of a node recursively. Returns true if the walk
 // should be aborted
 func reverseRecursiveWalk[T any](n *Node[T], fn WalkFn[T]) bool {
+	n.auditCheck()
+
 	// Visit the leaf values if any
 	if n.leaf != nil && fn(n.leaf.key, n.leaf.val) {
 		return true
@@ -348,7 +539,14 @@ func (n *Node[T]) clone(deep bool) *Node[T] {
 	n.processLazyRefCount()
 	nn := new(Node[T])
 	nn.refCount = n.refCount
-	if n.getMutateCh() != nil {
+	nn.settled = n.settled
+	nn.agg = n.agg
+	nn.leaves = n.leaves
+	nn.generation = n.generation
+	nn.labels = n.labels
+	nn.noWatch = n.noWatch
+	nn.noLeafWatch = n.noLeafWatch
+	if !n.noWatch {
 		nn.setMutateCh(n.getMutateCh())
 	}
 	if n.prefix != nil {
@@ -376,6 +574,45 @@ func (n *Node[T]) clone(deep bool) *Node[T] {
 	return nn
 }
 
+// deepClone returns a copy of the subtree under n that shares nothing with
+// it, not even the watch channels, using valueClone to copy the leaf values.
+func (n *Node[T]) deepClone(valueClone func(T) T) *Node[T] {
+	n.processLazyRefCount()
+	nn := &Node[T]{
+		refCount: n.refCount,
+		settled:  n.settled,
+		agg:      n.agg,
+		leaves:   n.leaves,
+
+		generation: n.generation,
+	}
+	if n.labels != nil {
+		labels := *n.labels
+		nn.labels = &labels
+	}
+	if n.prefix != nil {
+		nn.prefix = make([]byte, len(n.prefix))
+		copy(nn.prefix, n.prefix)
+	}
+	if n.leaf != nil {
+		nn.leaf = &leafNode[T]{
+			key:      make([]byte, len(n.leaf.key)),
+			val:      valueClone(n.leaf.val),
+			refCount: n.leaf.refCount,
+			extra:    n.leaf.extra.clone(),
+		}
+		copy(nn.leaf.key, n.leaf.key)
+	}
+	if len(n.edges) != 0 {
+		nn.edges = make([]edge[T], len(n.edges))
+		for idx, ed := range n.edges {
+			nn.edges[idx].label = ed.label
+			nn.edges[idx].node = ed.node.deepClone(valueClone)
+		}
+	}
+	return nn
+}
+
 func (n *Node[T]) getMutateCh() chan struct{} {
 	ch := n.mutateCh.Load()
 	if ch != nil && *ch != nil {
@@ -423,6 +660,7 @@ func (n *leafNode[T]) clone() *leafNode[T] {
 	nn.key = make([]byte, len(n.key))
 	copy(nn.key, n.key)
 	nn.val = n.val
+	nn.extra = n.extra.clone()
 	nn.setMutateCh(n.getMutateCh())
 	nn.refCount = n.refCount
 	return nn
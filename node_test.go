@@ -53,3 +53,50 @@ func TestNodeWalkBackwards(t *testing.T) {
 		return false
 	})
 }
+
+func TestNodeAggregates(t *testing.T) {
+	build := func(opts ...Option[int]) *Tree[int] {
+		txn := New[int](opts...).Txn(false)
+		for i, k := range []string{"a", "ab", "abc", "b"} {
+			txn.Insert([]byte(k), i+1)
+		}
+		return txn.Commit()
+	}
+	var walk func(n *Node[int], fn func(n *Node[int]))
+	walk = func(n *Node[int], fn func(n *Node[int])) {
+		fn(n)
+		for _, e := range n.edges {
+			walk(e.node, fn)
+		}
+	}
+
+	// Trees without the options don't allocate their state.
+	walk(build().Root(), func(n *Node[int]) {
+		if n.agg != nil || (n.leaf != nil && n.leaf.extra != nil) {
+			t.Fatalf("unexpected state on node %q", n.prefix)
+		}
+	})
+
+	r := build(
+		WithLeafHash(testLeafHash),
+		WithLeafWeight(func(_ []byte, v int) uint64 { return uint64(v) }),
+		WithSizer(func(v int) int { return v * 10 }),
+		WithInsertionOrder[int](),
+	)
+	if r.RootHash() == nil || r.Root().Weight() != 10 || r.Root().Size() != 100 {
+		t.Fatalf("bad aggregates: %x %d %d", r.RootHash(), r.Root().Weight(), r.Root().Size())
+	}
+	walk(r.Root(), func(n *Node[int]) {
+		if n.agg == nil || (n.leaf != nil && (n.leaf.getHash() == nil || n.leaf.getSeq() == 0)) {
+			t.Fatalf("missing state on node %q", n.prefix)
+		}
+	})
+
+	// A clone shares the aggregates, which a later commit replaces rather
+	// than modifies.
+	c := r.Clone()
+	r2, _, _ := r.Insert([]byte("abc"), 5)
+	if r2.Root().Weight() != 12 || c.Root().Weight() != 10 || r.Root().Weight() != 10 {
+		t.Fatalf("bad weights: %d %d %d", r2.Root().Weight(), c.Root().Weight(), r.Root().Weight())
+	}
+}
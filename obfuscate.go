@@ -0,0 +1,65 @@
+package iradix
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// KeyTransform protects a part of a key for ExportObfuscated. It is given the
+// key up to the end of a node's segment and returns the bytes replacing that
+// segment, which must not be empty.
+type KeyTransform func(path []byte) []byte
+
+// HMACKeys returns a KeyTransform replacing each segment with the first size
+// bytes of the HMAC-SHA256 of the path leading to it, keyed with secret. The
+// output can't be reversed or recomputed without the secret, but equal paths
+// give equal outputs, so the same secret gives comparable dumps. A size of 8
+// keeps the chance of siblings colliding negligible for most trees.
+func HMACKeys(secret []byte, size int) KeyTransform {
+	size = min(max(size, 1), sha256.Size)
+	return func(path []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(path)
+		return mac.Sum(nil)[:size]
+	}
+}
+
+// ExportObfuscated returns a copy of the tree under n with its keys protected
+// by transform and its values converted by value, which can omit them by
+// returning a zero value or encrypt them. This allows sharing structural
+// dumps, for example with vendors, without leaking identifiers held in the
+// keys.
+//
+// Keys are transformed one node segment at a time, so a key that is a prefix
+// of another, or that shares a node with another, still does so in the copy:
+// the shape of the tree survives, while the order of the keys doesn't. An
+// error from value stops the export and is returned.
+func ExportObfuscated[T, U any](n *Node[T], transform KeyTransform, value func(k []byte, v T) (U, error)) (*Tree[U], error) {
+	txn := New[U]().Txn(false)
+	var err error
+	var walk func(n *Node[T], path, out []byte) bool
+	walk = func(n *Node[T], path, out []byte) bool {
+		if len(n.prefix) > 0 {
+			path = concat(path, n.prefix)
+			out = concat(out, transform(path))
+		}
+		if n.leaf != nil {
+			var v U
+			if v, err = value(n.leaf.key, n.leaf.val); err != nil {
+				return true
+			}
+			txn.Insert(out, v)
+		}
+		for _, e := range n.edges {
+			if walk(e.node, path, out) {
+				return true
+			}
+		}
+		return false
+	}
+	walk(n, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return txn.Commit(), nil
+}
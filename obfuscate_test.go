@@ -0,0 +1,63 @@
+package iradix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestExportObfuscated(t *testing.T) {
+	r := New[string]()
+	for _, k := range []string{"tenant-acme/users/1", "tenant-acme/users/2", "tenant-acme", "tenant-globex/users/1"} {
+		r, _, _ = r.Insert([]byte(k), "secret "+k)
+	}
+
+	omit := func([]byte, string) (struct{}, error) { return struct{}{}, nil }
+	out, err := ExportObfuscated(r.Root(), HMACKeys([]byte("key"), 8), omit)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out.Len() != r.Len() {
+		t.Fatalf("bad len: %d", out.Len())
+	}
+
+	var keys [][]byte
+	out.Root().Walk(func(k []byte, _ struct{}) bool {
+		if bytes.Contains(k, []byte("acme")) || bytes.Contains(k, []byte("tenant")) {
+			t.Fatalf("leaked key: %q", k)
+		}
+		keys = append(keys, k)
+		return false
+	})
+
+	// The prefix relationships between the keys are kept.
+	prefixes := 0
+	for _, a := range keys {
+		for _, b := range keys {
+			if len(a) < len(b) && bytes.HasPrefix(b, a) {
+				prefixes++
+			}
+		}
+	}
+	if prefixes != 2 {
+		t.Fatalf("expected the acme key to prefix its two users, got %d", prefixes)
+	}
+
+	// The same secret gives the same dump, another one doesn't.
+	again, _ := ExportObfuscated(r.Root(), HMACKeys([]byte("key"), 8), omit)
+	other, _ := ExportObfuscated(r.Root(), HMACKeys([]byte("other"), 8), omit)
+	for _, k := range keys {
+		if _, ok := again.Get(k); !ok {
+			t.Fatalf("dumps differ for %q", k)
+		}
+		if _, ok := other.Get(k); ok {
+			t.Fatalf("dumps with different secrets match for %q", k)
+		}
+	}
+
+	boom := errors.New("boom")
+	_, err = ExportObfuscated(r.Root(), HMACKeys(nil, 8), func([]byte, string) (int, error) { return 0, boom })
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
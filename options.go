@@ -0,0 +1,88 @@
+package iradix
+
+import "time"
+
+// Option is used to configure optional behaviour of a Tree when it is
+// created with New. The options are carried over to every tree derived from
+// it through transactions.
+type Option[T any] func(*config[T])
+
+// config holds the optional behaviour of a tree.
+type config[T any] struct {
+	// leafHash is used to compute the content hash of leaves, see
+	// WithLeafHash.
+	leafHash LeafHashFn[T]
+
+	// leafWeight is used to compute the weight of leaves, see
+	// WithLeafWeight.
+	leafWeight LeafWeightFn[T]
+
+	// sizer is used to compute the size of values, see WithSizer.
+	sizer Sizer[T]
+
+	// keyValidator checks the keys being inserted, see WithKeyValidator.
+	keyValidator KeyValidator
+
+	// maxTxnMutations is the maximum number of mutations per transaction,
+	// or zero for no limit, see WithMaxTxnMutations.
+	maxTxnMutations int
+
+	// insertionOrder enables the insertion order index, see
+	// WithInsertionOrder.
+	insertionOrder bool
+
+	// interner deduplicates the values being inserted, see WithInterner.
+	interner *Interner[T]
+
+	// suffixIndex enables the suffix index, see WithSuffixIndex.
+	suffixIndex bool
+
+	// ngramSize is the length of the n-grams of the n-gram index, or zero
+	// if it is disabled, see WithNGramIndex.
+	ngramSize int
+
+	// lastModDepth is the length of the prefixes whose last modification
+	// is tracked, or zero if it is disabled, and clock gives the time of
+	// the modifications, see WithLastModified.
+	lastModDepth int
+	clock        func() time.Time
+
+	// recorder records the mutations applied, see WithRecorder.
+	recorder *Recorder[T]
+
+	// lookupCacheSize is the size of the lookup cache of committed trees,
+	// or zero if it is disabled, see WithLookupCache.
+	lookupCacheSize int
+
+	// priority gives the priority of values for the priority index, see
+	// WithPriority.
+	priority func(T) int64
+
+	// profileCommits enables commit profiling, and profiler is called with
+	// the profile of each commit if it is set, see WithCommitProfiler.
+	profileCommits bool
+	profiler       func(CommitProfile)
+
+	// watchAllocation selects which nodes allocate watch channels, see
+	// WithWatchAllocation.
+	watchAllocation WatchAllocation
+
+	// readRepair validates the values read by Tree.Get and queues the
+	// invalid ones for repair, see WithReadValidator.
+	readRepair *readRepair[T]
+}
+
+// newConfig builds a configuration from the given options.
+func newConfig[T any](opts []Option[T]) *config[T] {
+	c := &config[T]{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// settles returns true if the configuration needs any derived per-node state
+// to be maintained on commit, beyond the generation.
+func (c *config[T]) settles() bool {
+	return c != nil && (c.leafHash != nil || c.leafWeight != nil || c.sizer != nil)
+}
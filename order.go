@@ -0,0 +1,89 @@
+package iradix
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// WithInsertionOrder makes the tree maintain a secondary index of its keys in
+// the order they were inserted, so that it can also be iterated with
+// OldestFirst and NewestFirst. Updating the value of a key moves it to the
+// newest position. The index costs a second tree holding every key.
+func WithInsertionOrder[T any]() Option[T] {
+	return func(c *config[T]) {
+		c.insertionOrder = true
+	}
+}
+
+// orderIndex is the insertion order index of a tree, mapping sequence numbers
+// to the keys inserted with them.
+type orderIndex struct {
+	tree *Tree[[]byte]
+	seq  uint64
+}
+
+// seqKey returns the key of a sequence number in the insertion order index.
+func seqKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
+
+// orderInsert records a key about to be inserted as the newest in the
+// insertion order index, setting the sequence number for its new leaf.
+func (t *Txn[T]) orderInsert(k []byte) {
+	if old := t.root.leafFor(k); old != nil {
+		t.order.Delete(seqKey(old.getSeq()))
+	}
+	t.orderSeq++
+	t.order.Insert(seqKey(t.orderSeq), k)
+}
+
+// leafFor returns the leaf for the given key, or nil if there is none.
+func (n *Node[T]) leafFor(k []byte) *leafNode[T] {
+	if n = n.prefixNode(k); n != nil && n.leaf != nil && bytes.Equal(n.leaf.key, k) {
+		return n.leaf
+	}
+	return nil
+}
+
+// OrderIterator iterates over the entries of a tree in insertion order, see
+// WithInsertionOrder.
+type OrderIterator[T any] struct {
+	root *Node[T]
+	next func() ([]byte, []byte, bool)
+}
+
+// OldestFirst returns an iterator over the entries of the tree from the
+// oldest inserted to the newest. It returns no entries unless the tree was
+// created with WithInsertionOrder.
+func (t *Tree[T]) OldestFirst() *OrderIterator[T] {
+	it := &OrderIterator[T]{root: t.root}
+	if t.order != nil {
+		it.next = t.order.tree.Root().Iterator().Next
+	}
+	return it
+}
+
+// NewestFirst returns an iterator over the entries of the tree from the
+// newest inserted to the oldest. It returns no entries unless the tree was
+// created with WithInsertionOrder.
+func (t *Tree[T]) NewestFirst() *OrderIterator[T] {
+	it := &OrderIterator[T]{root: t.root}
+	if t.order != nil {
+		it.next = t.order.tree.Root().ReverseIterator().Previous
+	}
+	return it
+}
+
+// Next returns the next key and value, and false once the iteration is done.
+func (it *OrderIterator[T]) Next() ([]byte, T, bool) {
+	var zero T
+	if it.next == nil {
+		return nil, zero, false
+	}
+	_, k, ok := it.next()
+	if !ok {
+		return nil, zero, false
+	}
+	v, _ := it.root.Get(k)
+	return k, v, true
+}
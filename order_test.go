@@ -0,0 +1,50 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func orderKeys(it *OrderIterator[int]) []string {
+	var out []string
+	for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+		out = append(out, string(k))
+	}
+	return out
+}
+
+func TestInsertionOrder(t *testing.T) {
+	r := New[int](WithInsertionOrder[int]())
+	for i, k := range []string{"c", "a", "b/1", "b/2", "d"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	if got := orderKeys(r.OldestFirst()); !slices.Equal(got, []string{"c", "a", "b/1", "b/2", "d"}) {
+		t.Fatalf("bad: %v", got)
+	}
+
+	// Updates move to the newest position, deletes drop out.
+	txn := r.Txn(false)
+	txn.Insert([]byte("a"), 10)
+	txn.Delete([]byte("d"))
+	txn.DeletePrefix([]byte("b/"))
+	txn.Insert([]byte("e"), 11)
+	old := r
+	r = txn.Commit()
+	if got := orderKeys(r.NewestFirst()); !slices.Equal(got, []string{"e", "a", "c"}) {
+		t.Fatalf("bad: %v", got)
+	}
+	if _, v, _ := r.NewestFirst().Next(); v != 11 {
+		t.Fatalf("bad value: %d", v)
+	}
+
+	// The old tree keeps its own order.
+	if got := orderKeys(old.OldestFirst()); !slices.Equal(got, []string{"c", "a", "b/1", "b/2", "d"}) {
+		t.Fatalf("bad: %v", got)
+	}
+
+	// Without the option there is no order.
+	plain, _, _ := New[int]().Insert([]byte("a"), 1)
+	if got := orderKeys(plain.OldestFirst()); got != nil {
+		t.Fatalf("bad: %v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package iradix
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OrderedMap is a mutable ordered map built on a tree, with the usual
+// Get/Set/Delete/Len/Range methods of ordered map abstractions, so that the
+// tree can be dropped into code written against them. Reads use the current
+// tree without locking, and every read, including a Range, sees a consistent
+// snapshot. Writes are serialized and each commits a new tree. It is safe for
+// concurrent use.
+type OrderedMap[K ~string, V any] struct {
+	l    sync.Mutex
+	tree atomic.Pointer[Tree[V]]
+}
+
+// NewOrderedMap returns an empty OrderedMap whose tree is configured with the
+// given options.
+func NewOrderedMap[K ~string, V any](opts ...Option[V]) *OrderedMap[K, V] {
+	m := &OrderedMap[K, V]{}
+	m.tree.Store(New[V](opts...))
+	return m
+}
+
+// Get returns the value of the key, and false if it isn't in the map.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	return m.tree.Load().Get([]byte(key))
+}
+
+// Has returns true if the key is in the map.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Set sets the value of the key, returning the previous value and true if
+// it was already in the map.
+func (m *OrderedMap[K, V]) Set(key K, value V) (V, bool) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	t, old, ok := m.tree.Load().Insert([]byte(key), value)
+	m.tree.Store(t)
+	return old, ok
+}
+
+// Delete deletes the key, returning its value and true if it was in the map.
+func (m *OrderedMap[K, V]) Delete(key K) (V, bool) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	t, old, ok := m.tree.Load().Delete([]byte(key))
+	if ok {
+		m.tree.Store(t)
+	}
+	return old, ok
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return m.tree.Load().Len()
+}
+
+// Range calls fn for each key and value in key order, stopping if fn returns
+// false. It iterates over a snapshot, so writes made meanwhile, including by
+// fn, aren't seen.
+func (m *OrderedMap[K, V]) Range(fn func(key K, value V) bool) {
+	m.tree.Load().Root().Walk(func(k []byte, v V) bool {
+		return !fn(K(k), v)
+	})
+}
+
+// Snapshot returns the current tree, which can be read with the full API of
+// the package while the map keeps changing.
+func (m *OrderedMap[K, V]) Snapshot() *Tree[V] {
+	return m.tree.Load()
+}
@@ -0,0 +1,65 @@
+package iradix
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	for i, k := range []string{"b", "a", "c"} {
+		if _, ok := m.Set(k, i); ok {
+			t.Fatalf("%q should be new", k)
+		}
+	}
+	if old, ok := m.Set("a", 10); !ok || old != 1 {
+		t.Fatalf("bad: %v %v", old, ok)
+	}
+	if v, ok := m.Get("a"); !ok || v != 10 || !m.Has("c") || m.Has("z") || m.Len() != 3 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	snap := m.Snapshot()
+	var keys []string
+	m.Range(func(k string, _ int) bool {
+		keys = append(keys, k)
+		m.Delete(k)
+		return k != "b"
+	})
+	if fmt.Sprint(keys) != "[a b]" {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if m.Len() != 1 || snap.Len() != 3 {
+		t.Fatalf("bad: %d %d", m.Len(), snap.Len())
+	}
+	if _, ok := m.Delete("a"); ok {
+		t.Fatalf("already deleted")
+	}
+
+	type name string
+	named := NewOrderedMap[name, bool]()
+	named.Set("x", true)
+	if !named.Has(name("x")) {
+		t.Fatalf("missing")
+	}
+}
+
+func TestOrderedMap_Concurrent(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.Set(fmt.Sprintf("%d/%03d", w, i), i)
+				m.Range(func(string, int) bool { return true })
+			}
+		}(w)
+	}
+	wg.Wait()
+	if m.Len() != 400 {
+		t.Fatalf("bad len: %d", m.Len())
+	}
+}
@@ -0,0 +1,103 @@
+package iradix
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrInvalidCursor is returned by ParseCursor for a cursor that wasn't
+// produced by Page.
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// KV is a key and its value.
+type KV[T any] struct {
+	Key   []byte
+	Value T
+}
+
+// PageOptions selects the page returned by Page.
+type PageOptions struct {
+	// Prefix restricts the page to the keys under it.
+	Prefix []byte
+
+	// AfterKey starts the page after this key, in the direction of the
+	// iteration. It is normally the result of ParseCursor on the
+	// NextCursor of the previous page, and nil for the first page.
+	AfterKey []byte
+
+	// Limit is the maximum number of entries in the page. Zero or less
+	// means no limit.
+	Limit int
+
+	// Reverse pages through the keys in descending order.
+	Reverse bool
+}
+
+// PageResult is a page of entries returned by Page.
+type PageResult[T any] struct {
+	// Items are the entries of the page in iteration order.
+	Items []KV[T]
+
+	// NextCursor is an opaque cursor for the next page, or empty if this
+	// is the last page.
+	NextCursor string
+}
+
+// Page returns a page of the entries in the tree rooted at root, in key order
+// or in reverse key order. Since the tree is immutable, paging through a
+// single root gives a consistent listing; paging through successive roots
+// gives a listing in which every key present throughout is seen exactly once.
+func Page[T any](root *Node[T], opts PageOptions) PageResult[T] {
+	next := pageIterator(root, opts)
+	var res PageResult[T]
+	for {
+		k, v, ok := next()
+		if !ok || !bytes.HasPrefix(k, opts.Prefix) {
+			break
+		}
+		if opts.AfterKey != nil && bytes.Equal(k, opts.AfterKey) {
+			continue
+		}
+		if opts.Limit > 0 && len(res.Items) == opts.Limit {
+			res.NextCursor = base64.RawURLEncoding.EncodeToString(res.Items[len(res.Items)-1].Key)
+			break
+		}
+		res.Items = append(res.Items, KV[T]{Key: k, Value: v})
+	}
+	return res
+}
+
+// ParseCursor returns the AfterKey for the page following the one that
+// returned the given NextCursor.
+func ParseCursor(cursor string) ([]byte, error) {
+	k, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return k, nil
+}
+
+// pageIterator returns a function yielding the entries starting at the
+// beginning of the page.
+func pageIterator[T any](root *Node[T], opts PageOptions) func() ([]byte, T, bool) {
+	if !opts.Reverse {
+		start := opts.Prefix
+		if bytes.Compare(opts.AfterKey, start) > 0 {
+			start = opts.AfterKey
+		}
+		it := root.Iterator()
+		it.SeekLowerBound(start)
+		return it.Next
+	}
+
+	it := root.ReverseIterator()
+	if opts.AfterKey == nil ||
+		(bytes.Compare(opts.AfterKey, opts.Prefix) > 0 && !bytes.HasPrefix(opts.AfterKey, opts.Prefix)) {
+		// The page starts at the end of the prefix.
+		it.SeekPrefixEnd(opts.Prefix)
+	} else {
+		it.SeekReverseLowerBound(opts.AfterKey)
+	}
+	return it.Previous
+}
@@ -0,0 +1,85 @@
+package iradix
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func pageAll(t *testing.T, root *Node[int], opts PageOptions) [][]string {
+	var pages [][]string
+	for {
+		res := Page(root, opts)
+		var keys []string
+		for _, kv := range res.Items {
+			keys = append(keys, string(kv.Key))
+		}
+		pages = append(pages, keys)
+		if res.NextCursor == "" {
+			return pages
+		}
+		after, err := ParseCursor(res.NextCursor)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		opts.AfterKey = after
+	}
+}
+
+func TestPage(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a", "b/1", "b/2", "b/3", "b/4", "b/5", "c"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	cases := []struct {
+		opts PageOptions
+		want [][]string
+	}{
+		{
+			PageOptions{Prefix: []byte("b/"), Limit: 2},
+			[][]string{{"b/1", "b/2"}, {"b/3", "b/4"}, {"b/5"}},
+		},
+		{
+			PageOptions{Prefix: []byte("b/"), Limit: 5},
+			[][]string{{"b/1", "b/2", "b/3", "b/4", "b/5"}},
+		},
+		{
+			PageOptions{Prefix: []byte("b/"), Limit: 2, Reverse: true},
+			[][]string{{"b/5", "b/4"}, {"b/3", "b/2"}, {"b/1"}},
+		},
+		{
+			PageOptions{Limit: 3, Reverse: true},
+			[][]string{{"c", "b/5", "b/4"}, {"b/3", "b/2", "b/1"}, {"a"}},
+		},
+		{
+			PageOptions{Prefix: []byte("b/"), AfterKey: []byte("b/2")},
+			[][]string{{"b/3", "b/4", "b/5"}},
+		},
+		{
+			PageOptions{Prefix: []byte("b/"), AfterKey: []byte("a")},
+			[][]string{{"b/1", "b/2", "b/3", "b/4", "b/5"}},
+		},
+		{
+			PageOptions{Prefix: []byte("b/"), AfterKey: []byte("z"), Reverse: true},
+			[][]string{{"b/5", "b/4", "b/3", "b/2", "b/1"}},
+		},
+		{
+			PageOptions{Prefix: []byte("x")},
+			[][]string{nil},
+		},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			got := pageAll(t, r.Root(), c.opts)
+			if !slices.EqualFunc(got, c.want, slices.Equal[[]string]) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	if _, err := ParseCursor("not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("bad: %v", err)
+	}
+}
@@ -13,25 +13,44 @@ type PathIterator[T any] struct {
 
 // Next returns the next node in order
 func (i *PathIterator[T]) Next() ([]byte, T, bool) {
+	if n := i.nextLeaf(); n != nil {
+		return n.leaf.key, n.leaf.val, true
+	}
+
+	var zero T
+	return nil, zero, false
+}
+
+// NextWatch is like Next, but also returns the watch channel of the leaf,
+// which fires when its value is updated or it is deleted. Watching the
+// channels of every level consulted allows re-resolving when any of them
+// changes; a new level inserted along the path can be caught by also watching
+// the channel returned by GetWatch for the full path.
+func (i *PathIterator[T]) NextWatch() ([]byte, T, <-chan struct{}, bool) {
+	if n := i.nextLeaf(); n != nil {
+		return n.leaf.key, n.leaf.val, n.leafWatchCh(nil), true
+	}
+
+	var zero T
+	return nil, zero, nil, false
+}
+
+// nextLeaf returns the node holding the next leaf in order, or nil once the
+// path is done.
+func (i *PathIterator[T]) nextLeaf() *Node[T] {
 	// This is mostly just an asynchronous implementation of the WalkPath
 	// method on the node.
-	var zero T
-	var leaf *leafNode[T]
+	var found *Node[T]
 
-	for leaf == nil && i.node != nil {
+	for found == nil && i.node != nil {
 		// visit the leaf values if any
 		if i.node.leaf != nil {
-			leaf = i.node.leaf
+			found = i.node
 		}
 
 		i.iterate()
 	}
-
-	if leaf != nil {
-		return leaf.key, leaf.val, true
-	}
-
-	return nil, zero, false
+	return found
 }
 
 func (i *PathIterator[T]) iterate() {
@@ -102,3 +102,32 @@ func TestPathIterator(t *testing.T) {
 		}
 	}
 }
+
+func TestPathIterator_NextWatch(t *testing.T) {
+	r := New[any]()
+	for _, k := range []string{"foo", "foo/bar", "foo/bar/baz", "zip"} {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+
+	var keys []string
+	var watches []<-chan struct{}
+	it := r.Root().PathIterator([]byte("foo/bar/baz"))
+	for k, _, watch, ok := it.NextWatch(); ok; k, _, watch, ok = it.NextWatch() {
+		keys = append(keys, string(k))
+		watches = append(watches, watch)
+	}
+	if !reflect.DeepEqual(keys, []string{"foo", "foo/bar", "foo/bar/baz"}) {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	txn := r.Txn(true)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("foo/bar"), "updated")
+	txn.Insert([]byte("zip"), "updated")
+	r = txn.Commit()
+
+	fired := []bool{watchFired(watches[0]), watchFired(watches[1]), watchFired(watches[2])}
+	if !reflect.DeepEqual(fired, []bool{false, true, false}) {
+		t.Fatalf("bad: %v", fired)
+	}
+}
@@ -0,0 +1,79 @@
+package iradix
+
+import "encoding/binary"
+
+// WithPriority makes the tree maintain a secondary index of its keys ordered
+// by the priority fn gives their values, then by key, kept in sync by the
+// transactions and published with each commit. This lets the tree double as
+// a priority queue keyed by, say, job ID, with PeekMin and PopMin. The index
+// costs a second tree holding every key.
+func WithPriority[T any](fn func(T) int64) Option[T] {
+	return func(c *config[T]) {
+		c.priority = fn
+	}
+}
+
+// priorityKey returns the entry of the priority index for a key and its
+// value: the priority, with its sign bit flipped so that negative priorities
+// sort first, followed by the key.
+func (t *Txn[T]) priorityKey(k []byte, v T) []byte {
+	entry := make([]byte, 8, 8+len(k))
+	binary.BigEndian.PutUint64(entry, uint64(t.conf.priority(v))^(1<<63))
+	return append(entry, k...)
+}
+
+// priorityInsert moves a key about to be inserted to the position of its new
+// value in the priority index.
+func (t *Txn[T]) priorityInsert(k []byte, v T) {
+	if old := t.root.leafFor(k); old != nil {
+		t.priority.Delete(t.priorityKey(old.key, old.val))
+	}
+	t.priority.Insert(t.priorityKey(k, v), struct{}{})
+}
+
+// PeekMin returns the key with the lowest priority in the transaction and its
+// value, the smallest key winning ties, or false if there is none or the tree
+// wasn't created with WithPriority.
+func (t *Txn[T]) PeekMin() ([]byte, T, bool) {
+	if t.priority == nil {
+		var zero T
+		return nil, zero, false
+	}
+	return peekMin(t.root, t.priority.Root())
+}
+
+// PopMin is like PeekMin, but also deletes the key it returns. It returns
+// false if the delete is rejected, see TryDelete.
+func (t *Txn[T]) PopMin() ([]byte, T, bool) {
+	k, v, ok := t.PeekMin()
+	if !ok {
+		return nil, v, false
+	}
+	if _, _, err := t.TryDelete(k); err != nil {
+		var zero T
+		return nil, zero, false
+	}
+	return k, v, true
+}
+
+// PeekMin returns the key with the lowest priority in the tree and its value,
+// see Txn.PeekMin.
+func (t *Tree[T]) PeekMin() ([]byte, T, bool) {
+	if t.priority == nil {
+		var zero T
+		return nil, zero, false
+	}
+	return peekMin(t.root, t.priority.Root())
+}
+
+// peekMin returns the first key of a priority index and its value in root.
+func peekMin[T any](root *Node[T], index *Node[struct{}]) ([]byte, T, bool) {
+	entry, _, ok := index.Minimum()
+	if !ok {
+		var zero T
+		return nil, zero, false
+	}
+	k := entry[8:]
+	v, _ := root.Get(k)
+	return k, v, true
+}
@@ -0,0 +1,68 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPriority(t *testing.T) {
+	r := New[int](WithPriority(func(v int) int64 { return int64(v) }))
+	if _, _, ok := r.PeekMin(); ok {
+		t.Fatalf("should be empty")
+	}
+
+	txn := r.Txn(false)
+	txn.Insert([]byte("job-a"), 5)
+	txn.Insert([]byte("job-b"), -3)
+	txn.Insert([]byte("job-c"), 7)
+	txn.Insert([]byte("job-d"), 5)
+	if k, v, _ := txn.PeekMin(); string(k) != "job-b" || v != -3 {
+		t.Fatalf("bad: %q %d", k, v)
+	}
+
+	// Updating a value moves its key.
+	txn.Insert([]byte("job-b"), 10)
+	r = txn.Commit()
+	if k, v, _ := r.PeekMin(); string(k) != "job-a" || v != 5 {
+		t.Fatalf("bad: %q %d", k, v)
+	}
+
+	txn = r.Txn(false)
+	var got []string
+	for k, _, ok := txn.PopMin(); ok; k, _, ok = txn.PopMin() {
+		got = append(got, string(k))
+	}
+	want := []string{"job-a", "job-d", "job-c", "job-b"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v want %v", got, want)
+		}
+	}
+	if txn.Len() != 0 {
+		t.Fatalf("bad len: %d", txn.Len())
+	}
+	if k, _, _ := r.PeekMin(); string(k) != "job-a" {
+		t.Fatalf("old tree changed: %q", k)
+	}
+
+	// Deletes keep the index in sync.
+	r2, _ := r.DeletePrefix([]byte("job-a"))
+	r2, _, _ = r2.Delete([]byte("job-d"))
+	if k, _, _ := r2.PeekMin(); string(k) != "job-c" {
+		t.Fatalf("bad: %q", k)
+	}
+
+	// Rejected pops leave the key in place.
+	txn = r2.Txn(false)
+	txn.SealPrefix([]byte("job-c"))
+	if _, _, ok := txn.PopMin(); ok || !errors.Is(txn.Err(), ErrSealed) {
+		t.Fatalf("pop should be rejected: %v", txn.Err())
+	}
+
+	if _, _, ok := New[int]().Txn(false).PopMin(); ok {
+		t.Fatalf("no index")
+	}
+}
@@ -0,0 +1,79 @@
+package iradix
+
+import "time"
+
+// CommitProfile is the breakdown of the time taken by a transaction and its
+// commit, see WithCommitProfiler.
+type CommitProfile struct {
+	// Mutation is the time from the start of the transaction to its
+	// commit, spent applying the mutations.
+	Mutation time.Duration
+
+	// Refcount is the time spent settling the lazy reference counts.
+	Refcount time.Duration
+
+	// Settle is the time spent bringing the derived per-node state, such
+	// as hashes and sizes, up to date.
+	Settle time.Duration
+
+	// Publish is the time spent building the new tree and committing its
+	// secondary indexes.
+	Publish time.Duration
+
+	// Notify is the time spent closing the watch channels of the modified
+	// nodes. It is zero for CommitOnly, which doesn't notify.
+	Notify time.Duration
+
+	// Channels is the number of watch channels closed.
+	Channels int
+
+	// Total is the sum of the phases.
+	Total time.Duration
+}
+
+// WithCommitProfiler makes the tree's transactions time the phases of their
+// commits, so that latency spikes can be attributed to the right subsystem.
+// The profile of a transaction's last commit is returned by
+// Txn.LastCommitProfile, and fn, if not nil, is called with the profile of
+// every commit.
+func WithCommitProfiler[T any](fn func(CommitProfile)) Option[T] {
+	return func(c *config[T]) {
+		c.profileCommits = true
+		c.profiler = fn
+	}
+}
+
+// profiling returns true if commits are profiled.
+func (c *config[T]) profiling() bool {
+	return c != nil && c.profileCommits
+}
+
+// lap records the time since the last lap as the duration of a phase, if
+// commits are profiled.
+func (t *Txn[T]) lap(d *time.Duration) {
+	if !t.conf.profiling() {
+		return
+	}
+	now := time.Now()
+	*d = now.Sub(t.lapStart)
+	t.lapStart = now
+}
+
+// reportProfile completes the profile of a commit and reports it.
+func (t *Txn[T]) reportProfile() {
+	if !t.conf.profiling() {
+		return
+	}
+	p := &t.profile
+	p.Total = p.Mutation + p.Refcount + p.Settle + p.Publish + p.Notify
+	if t.conf.profiler != nil {
+		t.conf.profiler(*p)
+	}
+}
+
+// LastCommitProfile returns the profile of the last commit of the
+// transaction, or the zero profile if the tree wasn't created with
+// WithCommitProfiler.
+func (t *Txn[T]) LastCommitProfile() CommitProfile {
+	return t.profile
+}
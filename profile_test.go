@@ -0,0 +1,50 @@
+package iradix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommitProfiler(t *testing.T) {
+	var reported []CommitProfile
+	r := New[int](WithCommitProfiler[int](func(p CommitProfile) {
+		reported = append(reported, p)
+	}))
+
+	txn := r.Txn(false)
+	txn.TrackMutate(true)
+	for i := 0; i < 1000; i++ {
+		txn.Insert([]byte{byte(i >> 8), byte(i)}, i)
+	}
+	time.Sleep(time.Millisecond)
+	r = txn.Commit()
+
+	p := txn.LastCommitProfile()
+	if len(reported) != 1 || reported[0] != p {
+		t.Fatalf("bad reports: %v", reported)
+	}
+	if p.Mutation < time.Millisecond {
+		t.Fatalf("mutation phase too short: %v", p.Mutation)
+	}
+	if p.Channels == 0 {
+		t.Fatalf("channels should have been closed")
+	}
+	if p.Total != p.Mutation+p.Refcount+p.Settle+p.Publish+p.Notify || p.Total < p.Mutation {
+		t.Fatalf("bad total: %+v", p)
+	}
+
+	// CommitOnly doesn't notify.
+	txn = r.Txn(false)
+	txn.Insert([]byte("a"), 1)
+	txn.CommitOnly()
+	if p := txn.LastCommitProfile(); p.Notify != 0 || p.Channels != 0 || len(reported) != 2 {
+		t.Fatalf("bad: %+v", p)
+	}
+
+	txn = New[int]().Txn(false)
+	txn.Insert([]byte("a"), 1)
+	txn.Commit()
+	if p := txn.LastCommitProfile(); p != (CommitProfile{}) {
+		t.Fatalf("not profiled: %+v", p)
+	}
+}
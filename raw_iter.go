@@ -16,12 +16,17 @@ type rawIterator[T any] struct {
 	// path is the effective path of the current iterator position,
 	// regardless of whether the current node is a leaf.
 	path string
+
+	// depth is the number of edges from the starting node to the current
+	// iterator position.
+	depth int
 }
 
 // rawStackEntry is used to keep track of the cumulative common path as well as
 // its associated edges in the frontier.
 type rawStackEntry[T any] struct {
 	path  string
+	depth int
 	edges edges[T]
 }
 
@@ -65,14 +70,16 @@ func (i *rawIterator[T]) Next() {
 		// Push the edges onto the frontier.
 		if len(elem.edges) > 0 {
 			path := last.path + string(elem.prefix)
-			i.stack = append(i.stack, rawStackEntry[T]{path, elem.edges})
+			i.stack = append(i.stack, rawStackEntry[T]{path, last.depth + 1, elem.edges})
 		}
 
 		i.pos = elem
 		i.path = last.path + string(elem.prefix)
+		i.depth = last.depth
 		return
 	}
 
 	i.pos = nil
 	i.path = ""
+	i.depth = 0
 }
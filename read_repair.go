@@ -0,0 +1,183 @@
+package iradix
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ReadValidator checks a value read from the tree, returning an error if it
+// is corrupt or stale, for example because it references an external
+// resource that has disappeared.
+type ReadValidator[T any] func(k []byte, v T) error
+
+// ReadRepairFn returns the value replacing an invalid one, or false to delete
+// the key instead.
+type ReadRepairFn[T any] func(k []byte, v T, err error) (T, bool)
+
+// ReadRepairStats counts the work of a tree's read validator, see
+// WithReadValidator.
+type ReadRepairStats struct {
+	// Checked is the number of values validated and Invalid the number
+	// found invalid.
+	Checked uint64
+	Invalid uint64
+
+	// Repaired is the number of keys updated and Deleted the number
+	// deleted by the committed transactions of ApplyRepairs, while Pending
+	// are still queued.
+	Repaired uint64
+	Deleted  uint64
+	Pending  int
+}
+
+// WithReadValidator makes Tree.Get validate the values it finds. An invalid
+// value is reported as missing and its key is queued for repair, which is
+// done by a later transaction calling ApplyRepairs, since the tree itself is
+// immutable. The repair function gives the value replacing it, and if it is
+// nil the key is deleted instead. Both functions may be called concurrently.
+// The queue and the statistics, see ReadRepairStats, are shared by every tree
+// derived from the one created with the option.
+func WithReadValidator[T any](validate ReadValidator[T], repair ReadRepairFn[T]) Option[T] {
+	return func(c *config[T]) {
+		c.readRepair = &readRepair[T]{
+			validate: validate,
+			repair:   repair,
+			pending:  make(map[string]struct{}),
+		}
+	}
+}
+
+// readRepair holds the read validator of a tree and the keys queued for
+// repair.
+type readRepair[T any] struct {
+	validate ReadValidator[T]
+	repair   ReadRepairFn[T]
+
+	checked  atomic.Uint64
+	invalid  atomic.Uint64
+	repaired atomic.Uint64
+	deleted  atomic.Uint64
+
+	l       sync.Mutex
+	pending map[string]struct{}
+}
+
+// check validates a value found by Get, hiding it and queuing its key if it
+// is invalid.
+func (r *readRepair[T]) check(k []byte, v T) (T, bool) {
+	r.checked.Add(1)
+	if err := r.validate(k, v); err == nil {
+		return v, true
+	}
+	r.invalid.Add(1)
+	r.l.Lock()
+	r.pending[string(k)] = struct{}{}
+	r.l.Unlock()
+	var zero T
+	return zero, false
+}
+
+// ApplyRepairs repairs the keys queued by the tree's read validator, see
+// WithReadValidator, returning the number of keys updated or deleted. The
+// values are validated again first, and keys that are gone or were fixed in
+// the meantime are dropped too. The keys are only taken off the queue, and
+// the repairs counted, once the transaction commits, so a discarded
+// transaction leaves them for the next one. Keys whose repair is rejected,
+// for example by a sealed prefix, stay queued.
+func (t *Txn[T]) ApplyRepairs() int {
+	if t.conf == nil || t.conf.readRepair == nil {
+		return 0
+	}
+	r := t.conf.readRepair
+	r.l.Lock()
+	keys := make([]string, 0, len(r.pending))
+	for k := range r.pending {
+		keys = append(keys, k)
+	}
+	r.l.Unlock()
+
+	n := 0
+	for _, key := range keys {
+		k := []byte(key)
+		v, ok := t.Get(k)
+		var err error
+		if ok {
+			err = r.validate(k, v)
+		}
+		if err != nil {
+			if nv, keep := r.repairValue(k, v, err); keep {
+				if _, _, ierr := t.TryInsert(k, nv); ierr != nil {
+					continue
+				}
+				t.repairs.repaired++
+			} else {
+				if _, _, derr := t.TryDelete(k); derr != nil {
+					continue
+				}
+				t.repairs.deleted++
+			}
+			n++
+		}
+		t.repairs.keys = append(t.repairs.keys, key)
+	}
+	return n
+}
+
+// appliedRepairs are the keys handled by the ApplyRepairs calls of a
+// transaction and the number of keys they updated and deleted.
+type appliedRepairs struct {
+	keys              []string
+	repaired, deleted uint64
+}
+
+// clone returns a copy for a cloned transaction.
+func (a appliedRepairs) clone() appliedRepairs {
+	a.keys = append([]string(nil), a.keys...)
+	return a
+}
+
+// flushRepairs takes the keys handled by ApplyRepairs off the queue and
+// counts the repairs, as the transaction commits.
+func (t *Txn[T]) flushRepairs() {
+	if t.conf == nil || t.conf.readRepair == nil || len(t.repairs.keys) == 0 {
+		return
+	}
+	r := t.conf.readRepair
+	r.l.Lock()
+	for _, key := range t.repairs.keys {
+		delete(r.pending, key)
+	}
+	r.l.Unlock()
+	r.repaired.Add(t.repairs.repaired)
+	r.deleted.Add(t.repairs.deleted)
+	t.repairs = appliedRepairs{}
+}
+
+// repairValue returns the replacement of an invalid value, or false if the
+// key should be deleted.
+func (r *readRepair[T]) repairValue(k []byte, v T, err error) (T, bool) {
+	if r.repair == nil {
+		var zero T
+		return zero, false
+	}
+	return r.repair(k, v, err)
+}
+
+// ReadRepairStats returns the statistics of the tree's read validator, which
+// are zero if it has none, see WithReadValidator.
+func (t *Tree[T]) ReadRepairStats() ReadRepairStats {
+	if t.conf == nil || t.conf.readRepair == nil {
+		return ReadRepairStats{}
+	}
+	r := t.conf.readRepair
+	r.l.Lock()
+	pending := len(r.pending)
+	r.l.Unlock()
+	return ReadRepairStats{
+		Checked:  r.checked.Load(),
+		Invalid:  r.invalid.Load(),
+		Repaired: r.repaired.Load(),
+		Deleted:  r.deleted.Load(),
+		Pending:  pending,
+	}
+}
@@ -0,0 +1,81 @@
+package iradix
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadValidator(t *testing.T) {
+	errStale := errors.New("stale")
+	validate := func(k []byte, v string) error {
+		if strings.HasPrefix(v, "stale") {
+			return errStale
+		}
+		return nil
+	}
+	repair := func(k []byte, v string, err error) (string, bool) {
+		if err != errStale {
+			t.Fatalf("bad error: %v", err)
+		}
+		if strings.HasPrefix(string(k), "tmp/") {
+			return "", false
+		}
+		return "fresh", true
+	}
+
+	txn := New[string](WithReadValidator[string](validate, repair)).Txn(false)
+	txn.Insert([]byte("a"), "ok")
+	txn.Insert([]byte("b"), "stale1")
+	txn.Insert([]byte("tmp/c"), "stale2")
+	txn.Insert([]byte("d"), "stale3")
+	r := txn.Commit()
+
+	if v, ok := r.Get([]byte("a")); !ok || v != "ok" {
+		t.Fatalf("bad: %q %v", v, ok)
+	}
+	for _, k := range []string{"b", "tmp/c", "d", "b"} {
+		if v, ok := r.Get([]byte(k)); ok {
+			t.Fatalf("%q: invalid value should be hidden: %q", k, v)
+		}
+	}
+	if _, ok := r.Get([]byte("missing")); ok {
+		t.Fatalf("should be missing")
+	}
+	want := ReadRepairStats{Checked: 5, Invalid: 4, Pending: 3}
+	if got := r.ReadRepairStats(); got != want {
+		t.Fatalf("bad stats: %+v", got)
+	}
+
+	// Repairs of a discarded transaction stay queued.
+	if n := r.Txn(false).ApplyRepairs(); n != 3 {
+		t.Fatalf("bad number of repairs: %d", n)
+	}
+	if got := r.ReadRepairStats(); got != want {
+		t.Fatalf("bad stats: %+v", got)
+	}
+
+	// d gets fixed before the repair runs.
+	txn = r.Txn(false)
+	txn.Insert([]byte("d"), "fixed")
+	if n := txn.ApplyRepairs(); n != 2 {
+		t.Fatalf("bad number of repairs: %d", n)
+	}
+	r2 := txn.Commit()
+	if v, ok := r2.Get([]byte("b")); !ok || v != "fresh" {
+		t.Fatalf("bad: %q %v", v, ok)
+	}
+	if _, ok := r2.Root().Get([]byte("tmp/c")); ok {
+		t.Fatalf("should be deleted")
+	}
+	if v, _ := r2.Get([]byte("d")); v != "fixed" {
+		t.Fatalf("bad: %q", v)
+	}
+	want = ReadRepairStats{Checked: 7, Invalid: 4, Repaired: 1, Deleted: 1}
+	if got := r2.ReadRepairStats(); got != want {
+		t.Fatalf("bad stats: %+v", got)
+	}
+	if n := r2.Txn(false).ApplyRepairs(); n != 0 {
+		t.Fatalf("nothing should be left: %d", n)
+	}
+}
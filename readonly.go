@@ -0,0 +1,36 @@
+package iradix
+
+// ReadOnlyTree is a read-only view of a Tree. It exposes the lookup and
+// iteration operations but no way to start a transaction or get at the
+// underlying Tree, so handing one to another component guarantees at compile
+// time that it can only read.
+type ReadOnlyTree[T any] struct {
+	tree *Tree[T]
+}
+
+// ReadOnly returns a read-only view of the tree.
+func (t *Tree[T]) ReadOnly() ReadOnlyTree[T] {
+	return ReadOnlyTree[T]{tree: t}
+}
+
+// Len is used to return the number of elements in the tree
+func (t ReadOnlyTree[T]) Len() int {
+	return t.tree.Len()
+}
+
+// Root returns the root node of the tree which can be used for richer
+// query operations. Nodes have no mutating operations.
+func (t ReadOnlyTree[T]) Root() *Node[T] {
+	return t.tree.Root()
+}
+
+// Get is used to lookup a specific key, returning
+// the value and if it was found
+func (t ReadOnlyTree[T]) Get(k []byte) (T, bool) {
+	return t.tree.Get(k)
+}
+
+// RootHash returns the content hash of the whole tree, see Tree.RootHash.
+func (t ReadOnlyTree[T]) RootHash() []byte {
+	return t.tree.RootHash()
+}
@@ -0,0 +1,31 @@
+package iradix
+
+import "testing"
+
+func TestReadOnlyTree(t *testing.T) {
+	r := New[int]()
+	r, _, _ = r.Insert([]byte("foo"), 1)
+	r, _, _ = r.Insert([]byte("foobar"), 2)
+
+	ro := r.ReadOnly()
+	if ro.Len() != 2 {
+		t.Fatalf("bad len: %d", ro.Len())
+	}
+	if v, ok := ro.Get([]byte("foobar")); !ok || v != 2 {
+		t.Fatalf("bad get: %v %v", v, ok)
+	}
+	if k, _, ok := ro.Root().LongestPrefix([]byte("foozip")); !ok || string(k) != "foo" {
+		t.Fatalf("bad longest prefix: %q", k)
+	}
+
+	// The view keeps reading the version it was created from.
+	r.Insert([]byte("zip"), 3)
+	if _, ok := ro.Get([]byte("zip")); ok {
+		t.Fatalf("view changed")
+	}
+
+	// Views of the same tree are interchangeable.
+	if ro != r.ReadOnly() {
+		t.Fatalf("views of the same tree should be equal")
+	}
+}
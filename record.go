@@ -0,0 +1,195 @@
+package iradix
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// ErrCorruptLog is returned by Replay for a log that can't be decoded.
+var ErrCorruptLog = errors.New("corrupt operation log")
+
+// The kinds of the operations in a Recorder's log.
+const (
+	recordInsert byte = iota + 1
+	recordDelete
+	recordDeletePrefix
+	recordCommit
+)
+
+// Recorder captures the mutations applied to a tree into a compact log, see
+// WithRecorder, which Replay can turn back into the tree they produced. This
+// makes it possible to turn an anomaly seen in production into a unit test.
+// Values aren't recorded, only fingerprints of them, so that the log stays
+// small and doesn't leak their contents. A Recorder is safe for concurrent
+// use.
+type Recorder[T any] struct {
+	fingerprint func(T) uint64
+
+	l   sync.Mutex
+	log []byte
+
+	// next is the identifier of the last commit recorded.
+	next uint64
+}
+
+// NewRecorder returns an empty Recorder using fingerprint to summarize the
+// values inserted. It may be nil to record no fingerprints.
+func NewRecorder[T any](fingerprint func(T) uint64) *Recorder[T] {
+	return &Recorder[T]{fingerprint: fingerprint}
+}
+
+// WithRecorder makes the tree's transactions record the mutations they apply
+// into rec. A transaction's mutations are only added to the log when it is
+// committed, along with the tree it was started from, so that aborted
+// transactions leave no trace and transactions branched from the same tree
+// can be told apart. Rejected mutations and deletes of missing keys aren't
+// recorded.
+func WithRecorder[T any](rec *Recorder[T]) Option[T] {
+	return func(c *config[T]) {
+		c.recorder = rec
+	}
+}
+
+// Log returns a copy of the log recorded so far.
+func (r *Recorder[T]) Log() []byte {
+	r.l.Lock()
+	defer r.l.Unlock()
+	return append([]byte(nil), r.log...)
+}
+
+// Reset empties the log. Commits recorded afterwards on trees committed
+// before are replayed as if those trees were empty.
+func (r *Recorder[T]) Reset() {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.log = nil
+}
+
+// encode appends an operation to buf. The fingerprint is only written for
+// inserts.
+func (r *Recorder[T]) encode(buf []byte, kind byte, k []byte, v T) []byte {
+	buf = append(buf, kind)
+	buf = binary.AppendUvarint(buf, uint64(len(k)))
+	buf = append(buf, k...)
+	if kind == recordInsert {
+		var fp uint64
+		if r.fingerprint != nil {
+			fp = r.fingerprint(v)
+		}
+		buf = binary.AppendUvarint(buf, fp)
+	}
+	return buf
+}
+
+// commit appends the operations of a transaction started from the tree
+// identified by parent to the log, followed by a commit marker, and returns
+// the identifier of the committed tree.
+func (r *Recorder[T]) commit(parent uint64, ops []byte) uint64 {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.next++
+	r.log = append(r.log, ops...)
+	r.log = append(r.log, recordCommit)
+	r.log = binary.AppendUvarint(r.log, parent)
+	r.log = binary.AppendUvarint(r.log, r.next)
+	return r.next
+}
+
+// record buffers an operation until the transaction is committed, if the
+// tree has a recorder.
+func (t *Txn[T]) record(kind byte, k []byte, v T) {
+	if t.conf != nil && t.conf.recorder != nil {
+		t.recorded = t.conf.recorder.encode(t.recorded, kind, k, v)
+	}
+}
+
+// flushRecorded adds the operations buffered by the transaction to the log
+// of the recorder, if any, and returns the identifier of the committed tree.
+func (t *Txn[T]) flushRecorded() uint64 {
+	if t.conf == nil || t.conf.recorder == nil {
+		return 0
+	}
+	t.recordParent = t.conf.recorder.commit(t.recordParent, t.recorded)
+	t.recorded = t.recorded[:0]
+	return t.recordParent
+}
+
+// recordOp is an operation of a log being replayed.
+type recordOp struct {
+	kind byte
+	key  []byte
+	fp   uint64
+}
+
+// Replay rebuilds the tree produced by the mutations of a log recorded by a
+// Recorder, starting from an empty tree configured with the given options. As
+// values aren't recorded, value is called for each insert with the key and
+// the fingerprint of the value that was inserted. Each commit is replayed on
+// top of the tree its transaction was started from, and the tree of the last
+// commit in the log is returned. Operations after the last commit, such as
+// those of a truncated log, are dropped.
+func Replay[T any](log []byte, value func(k []byte, fingerprint uint64) T, opts ...Option[T]) (*Tree[T], error) {
+	empty := New[T](opts...)
+	last := empty
+	trees := make(map[uint64]*Tree[T])
+	var ops []recordOp
+	for len(log) > 0 {
+		kind := log[0]
+		log = log[1:]
+		if kind == recordCommit {
+			parent, read := binary.Uvarint(log)
+			if read <= 0 {
+				return nil, ErrCorruptLog
+			}
+			log = log[read:]
+			id, read := binary.Uvarint(log)
+			if read <= 0 {
+				return nil, ErrCorruptLog
+			}
+			log = log[read:]
+
+			base, ok := trees[parent]
+			if !ok {
+				base = empty
+			}
+			txn := base.Txn(false)
+			for _, op := range ops {
+				switch op.kind {
+				case recordInsert:
+					txn.Insert(op.key, value(op.key, op.fp))
+				case recordDelete:
+					txn.Delete(op.key)
+				case recordDeletePrefix:
+					txn.DeletePrefix(op.key)
+				}
+			}
+			last = txn.Commit()
+			trees[id] = last
+			ops = ops[:0]
+			continue
+		}
+
+		n, read := binary.Uvarint(log)
+		if read <= 0 || uint64(len(log)-read) < n {
+			return nil, ErrCorruptLog
+		}
+		op := recordOp{kind: kind, key: append([]byte(nil), log[read:read+int(n)]...)}
+		log = log[read+int(n):]
+
+		switch kind {
+		case recordInsert:
+			fp, read := binary.Uvarint(log)
+			if read <= 0 {
+				return nil, ErrCorruptLog
+			}
+			log = log[read:]
+			op.fp = fp
+		case recordDelete, recordDeletePrefix:
+		default:
+			return nil, ErrCorruptLog
+		}
+		ops = append(ops, op)
+	}
+	return last, nil
+}
@@ -0,0 +1,138 @@
+package iradix
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	rec := NewRecorder(func(v int) uint64 { return uint64(v) })
+	r := New[int](WithRecorder(rec), WithMaxTxnMutations[int](4))
+
+	txn := r.Txn(false)
+	txn.Insert([]byte("a"), 1)
+	txn.Insert([]byte("b/1"), 2)
+	txn.Insert([]byte("b/2"), 3)
+	txn.Delete([]byte("missing"))
+	r = txn.Commit()
+
+	txn = r.Txn(false)
+	txn.DeletePrefix([]byte("b/"))
+	txn.Insert([]byte("c"), 4)
+	txn.Delete([]byte("a"))
+	txn.Insert([]byte("d"), 5)
+	txn.Insert([]byte("rejected"), 6)
+	r = txn.Commit()
+	r, _, _ = r.Insert([]byte("e"), 7)
+
+	got, err := Replay(rec.Log(), func(_ []byte, fp uint64) int { return int(fp) })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	dump := func(r *Tree[int]) map[string]int {
+		m := make(map[string]int)
+		r.Root().Walk(func(k []byte, v int) bool {
+			m[string(k)] = v
+			return false
+		})
+		return m
+	}
+	want := map[string]int{"c": 4, "e": 7}
+	if !reflect.DeepEqual(dump(got), want) || !reflect.DeepEqual(dump(r), want) {
+		t.Fatalf("got %v want %v", dump(got), dump(r))
+	}
+
+	// Uncommitted and aborted transactions aren't recorded.
+	txn = r.Txn(false)
+	txn.Insert([]byte("f"), 8)
+	aborted := r.Txn(false)
+	aborted.Delete([]byte("c"))
+	got, _ = Replay(rec.Log(), func(_ []byte, fp uint64) int { return int(fp) })
+	if !reflect.DeepEqual(dump(got), want) {
+		t.Fatalf("got %v want %v", dump(got), want)
+	}
+	r = txn.Commit()
+	want["f"] = 8
+	got, _ = Replay(rec.Log(), func(_ []byte, fp uint64) int { return int(fp) })
+	if !reflect.DeepEqual(dump(got), want) {
+		t.Fatalf("got %v want %v", dump(got), want)
+	}
+
+	rec.Reset()
+	if len(rec.Log()) != 0 {
+		t.Fatalf("should be empty")
+	}
+}
+
+func TestReplay_Corrupt(t *testing.T) {
+	rec := NewRecorder[int](nil)
+	r := New[int](WithRecorder(rec))
+	r.Insert([]byte("key"), 1)
+	log := rec.Log()
+
+	value := func([]byte, uint64) int { return 0 }
+	for _, bad := range [][]byte{log[:len(log)-2], {recordInsert, 10, 'a'}, {99}} {
+		if _, err := Replay(bad, value); !errors.Is(err, ErrCorruptLog) {
+			t.Fatalf("log %v: expected ErrCorruptLog, got %v", bad, err)
+		}
+	}
+}
+
+func TestRecorderReplay_Branches(t *testing.T) {
+	rec := NewRecorder(func(v int) uint64 { return uint64(v) })
+	base, _, _ := New[int](WithRecorder(rec)).Insert([]byte("base"), 1)
+
+	// Two transactions branched from the same tree, committed in turn and
+	// interleaved with a clone of one of them.
+	left := base.Txn(false)
+	right := base.Txn(false)
+	left.Insert([]byte("left"), 2)
+	clone := left.Clone()
+	right.Insert([]byte("right"), 3)
+	left.Commit()
+	clone.Insert([]byte("clone"), 4)
+	clone.Commit()
+	r := right.Commit()
+
+	value := func(_ []byte, fp uint64) int { return int(fp) }
+	got, err := Replay(rec.Log(), value)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for _, k := range []string{"base", "right"} {
+		if _, ok := got.Get([]byte(k)); !ok {
+			t.Fatalf("missing %q", k)
+		}
+	}
+	if got.Len() != r.Len() {
+		t.Fatalf("bad len: %d", got.Len())
+	}
+
+	// A transaction continued after its commit builds on that commit.
+	txn := r.Txn(false)
+	txn.Insert([]byte("a"), 5)
+	txn.Commit()
+	txn.Delete([]byte("base"))
+	r = txn.Commit()
+	got, _ = Replay(rec.Log(), value)
+	if _, ok := got.Get([]byte("base")); ok || got.Len() != r.Len() {
+		t.Fatalf("bad: %d", got.Len())
+	}
+
+	// Operations after the last commit are dropped.
+	log := rec.Log()
+	log = append(log, recordInsert, 1, 'z', 9)
+	if got, err := Replay(log, value); err != nil || got.Len() != r.Len() {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// The replayed keys don't alias the log.
+	got, _ = Replay(log, value)
+	for i := range log {
+		log[i] = 0
+	}
+	if _, ok := got.Get([]byte("right")); !ok {
+		t.Fatalf("keys should be copied")
+	}
+}
@@ -38,6 +38,21 @@ func (ri *ReverseIterator[T]) SeekPrefix(prefix []byte) {
 	ri.i.SeekPrefixWatch(prefix)
 }
 
+// SeekPrefixEnd is used to seek the iterator to the largest key under the
+// given prefix. Unlike SeekPrefix, iteration then carries on past the start of
+// the prefix into the smaller keys. If there are no keys under the prefix, the
+// iterator is positioned at the largest key before it.
+func (ri *ReverseIterator[T]) SeekPrefixEnd(prefix []byte) {
+	if ri.i.node != nil {
+		if n := ri.i.node.prefixNode(prefix); n != nil {
+			if k, _, ok := n.Maximum(); ok {
+				prefix = k
+			}
+		}
+	}
+	ri.SeekReverseLowerBound(prefix)
+}
+
 // SeekReverseLowerBound is used to seek the iterator to the largest key that is
 // lower or equal to the given key. There is no watch variant as it's hard to
 // predict based on the radix structure which node(s) changes might affect the
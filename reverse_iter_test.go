@@ -316,6 +316,40 @@ func TestReverseIterator_SeekPrefix(t *testing.T) {
 	}
 }
 
+func TestReverseIterator_SeekPrefixEnd(t *testing.T) {
+	r := New[any]()
+	keys := []string{"001", "002", "005", "010", "0100", "100"}
+	for _, k := range keys {
+		r, _, _ = r.Insert([]byte(k), nil)
+	}
+
+	cases := []struct {
+		prefix string
+		want   []string
+	}{
+		{"00", []string{"005", "002", "001"}},
+		{"01", []string{"0100", "010", "005", "002", "001"}},
+		{"010", []string{"0100", "010", "005", "002", "001"}},
+		{"", []string{"100", "0100", "010", "005", "002", "001"}},
+		{"05", []string{"0100", "010", "005", "002", "001"}},
+		{"000", nil},
+		{"2", []string{"100", "0100", "010", "005", "002", "001"}},
+	}
+	for _, c := range cases {
+		t.Run(c.prefix, func(t *testing.T) {
+			it := r.Root().ReverseIterator()
+			it.SeekPrefixEnd([]byte(c.prefix))
+			var got []string
+			for k, _, ok := it.Previous(); ok; k, _, ok = it.Previous() {
+				got = append(got, string(k))
+			}
+			if !slices.Equal(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
 func TestReverseIterator_SeekPrefixWatch(t *testing.T) {
 	key := []byte("key")
 
@@ -0,0 +1,149 @@
+package iradix
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// Ring is an immutable consistent hashing ring. Every member is placed on the
+// ring at a number of points given by hashing its name, and an item is owned
+// by the member of the first point at or after the item's hash, wrapping
+// around at the end of the ring. Adding or removing a member only moves the
+// items of the ring segments next to its points.
+type Ring[T any] struct {
+	tree   *Tree[ringPoint[T]]
+	points int
+	hash   func([]byte) uint64
+}
+
+// ringPoint is the value stored for a point of a Ring.
+type ringPoint[T any] struct {
+	name string
+	val  T
+}
+
+// RingMove is a segment of the hash space of a Ring whose owner changed. The
+// segment holds the hashes h with Start < h <= End, wrapping around the end
+// of the hash space if Start >= End.
+type RingMove struct {
+	Start, End uint64
+	From, To   string
+}
+
+// NewRing returns an empty ring placing each member at the given number of
+// points, using hash to hash the member names into points.
+func NewRing[T any](points int, hash func([]byte) uint64) *Ring[T] {
+	return &Ring[T]{
+		tree:   New[ringPoint[T]](),
+		points: points,
+		hash:   hash,
+	}
+}
+
+// pointKeys returns the keys of the points of a member. The key is the big
+// endian point hash, followed by the name to tell apart members whose points
+// collide.
+func (r *Ring[T]) pointKeys(name string) [][]byte {
+	keys := make([][]byte, r.points)
+	for i := range keys {
+		h := r.hash(strconv.AppendInt([]byte(name+"#"), int64(i), 10))
+		keys[i] = append(binary.BigEndian.AppendUint64(nil, h), name...)
+	}
+	return keys
+}
+
+// Add returns a new ring with the named member added, or its value updated if
+// it is already a member.
+func (r *Ring[T]) Add(name string, v T) *Ring[T] {
+	txn := r.tree.Txn(false)
+	for _, k := range r.pointKeys(name) {
+		txn.Insert(k, ringPoint[T]{name: name, val: v})
+	}
+	return &Ring[T]{tree: txn.Commit(), points: r.points, hash: r.hash}
+}
+
+// Remove returns a new ring without the named member.
+func (r *Ring[T]) Remove(name string) *Ring[T] {
+	txn := r.tree.Txn(false)
+	for _, k := range r.pointKeys(name) {
+		txn.Delete(k)
+	}
+	return &Ring[T]{tree: txn.Commit(), points: r.points, hash: r.hash}
+}
+
+// Lookup returns the name and value of the member owning the given item hash,
+// and false if the ring is empty.
+func (r *Ring[T]) Lookup(itemHash uint64) (string, T, bool) {
+	it := r.ceiling(itemHash)
+	if _, p, ok := it.Next(); ok {
+		return p.name, p.val, true
+	}
+	if _, p, ok := r.tree.Root().Minimum(); ok {
+		return p.name, p.val, true
+	}
+	var zero T
+	return "", zero, false
+}
+
+// LookupN returns the names of up to n distinct members for the given item
+// hash, in ring order starting with its owner, which is the usual choice of
+// replicas for the item.
+func (r *Ring[T]) LookupN(itemHash uint64, n int) []string {
+	var names []string
+	seen := make(map[string]bool)
+	visit := func(_ []byte, p ringPoint[T]) bool {
+		if !seen[p.name] {
+			seen[p.name] = true
+			names = append(names, p.name)
+		}
+		return len(names) == n
+	}
+	it := r.ceiling(itemHash)
+	for k, p, ok := it.Next(); ok; k, p, ok = it.Next() {
+		if visit(k, p) {
+			return names
+		}
+	}
+	// Wrap around to the start of the ring
+	r.tree.Root().Walk(visit)
+	return names
+}
+
+// ceiling returns an iterator positioned at the first point at or after the
+// given hash.
+func (r *Ring[T]) ceiling(h uint64) *Iterator[ringPoint[T]] {
+	it := r.tree.Root().Iterator()
+	it.SeekLowerBound(binary.BigEndian.AppendUint64(nil, h))
+	return it
+}
+
+// Diff returns the segments of the hash space whose owner is different in the
+// other ring, in hash order, which are the items to move when changing from r
+// to other.
+func (r *Ring[T]) Diff(other *Ring[T]) []RingMove {
+	// Every segment boundary is a point of one of the rings
+	var bounds []uint64
+	it := NewMergeIterator(r.tree.Root(), other.tree.Root())
+	for k, _, _, ok := it.Next(); ok; k, _, _, ok = it.Next() {
+		h := binary.BigEndian.Uint64(k)
+		if len(bounds) == 0 || bounds[len(bounds)-1] != h {
+			bounds = append(bounds, h)
+		}
+	}
+
+	var moves []RingMove
+	for i, end := range bounds {
+		start := bounds[(i+len(bounds)-1)%len(bounds)]
+		from, _, _ := r.Lookup(end)
+		to, _, _ := other.Lookup(end)
+		if from == to {
+			continue
+		}
+		if n := len(moves); n > 0 && moves[n-1].End == start && moves[n-1].From == from && moves[n-1].To == to {
+			moves[n-1].End = end
+			continue
+		}
+		moves = append(moves, RingMove{Start: start, End: end, From: from, To: to})
+	}
+	return moves
+}
@@ -0,0 +1,97 @@
+package iradix
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func fnvHash(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](16, fnvHash)
+	if _, _, ok := r.Lookup(42); ok {
+		t.Fatalf("empty ring should own nothing")
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		r = r.Add(name, i)
+	}
+
+	// Lookups agree with a brute force search of the points.
+	owners := map[string]int{"a": 0, "b": 1, "c": 2}
+	type point struct {
+		h    uint64
+		name string
+	}
+	var points []point
+	for name := range owners {
+		for _, k := range r.pointKeys(name) {
+			points = append(points, point{binary.BigEndian.Uint64(k), name})
+		}
+	}
+	slices.SortFunc(points, func(a, b point) int { return cmp.Compare(a.h, b.h) })
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		h := rng.Uint64()
+		want := points[0].name
+		for _, p := range points {
+			if p.h >= h {
+				want = p.name
+				break
+			}
+		}
+		got, v, ok := r.Lookup(h)
+		if !ok || got != want || v != owners[want] {
+			t.Fatalf("bad lookup: %q %d, want %q", got, v, want)
+		}
+	}
+
+	// Replicas are distinct members.
+	replicas := r.LookupN(rng.Uint64(), 5)
+	if len(replicas) != 3 {
+		t.Fatalf("bad: %v", replicas)
+	}
+	sorted := slices.Clone(replicas)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, []string{"a", "b", "c"}) {
+		t.Fatalf("bad: %v", replicas)
+	}
+
+	// The diff covers exactly the items that change owner.
+	r2 := r.Remove("b").Add("d", 3)
+	moves := r.Diff(r2)
+	if len(moves) == 0 {
+		t.Fatalf("expected moves")
+	}
+	inMoves := func(h uint64) (RingMove, bool) {
+		for _, m := range moves {
+			if (m.Start < m.End && h > m.Start && h <= m.End) ||
+				(m.Start >= m.End && (h > m.Start || h <= m.End)) {
+				return m, true
+			}
+		}
+		return RingMove{}, false
+	}
+	for i := 0; i < 1000; i++ {
+		h := rng.Uint64()
+		from, _, _ := r.Lookup(h)
+		to, _, _ := r2.Lookup(h)
+		m, moved := inMoves(h)
+		if moved != (from != to) {
+			t.Fatalf("hash %d: moved %v but owners %q -> %q", h, moved, from, to)
+		}
+		if moved && (m.From != from || m.To != to) {
+			t.Fatalf("bad move %+v for %q -> %q", m, from, to)
+		}
+		if from != "b" && to != "d" && from != to {
+			t.Fatalf("only b's items and d's new items should move")
+		}
+	}
+}
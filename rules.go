@@ -0,0 +1,108 @@
+package iradix
+
+// Rule is an entry of a RuleSet: a value applying to the keys under Prefix.
+type Rule[T any] struct {
+	Prefix   []byte
+	Priority int
+	Value    T
+}
+
+// Shadowed reports a rule of a RuleSet that can never match because another
+// rule for the same prefix always wins over it.
+type Shadowed[T any] struct {
+	Rule Rule[T]
+	By   Rule[T]
+}
+
+// RuleSet is an immutable set of prefix rules, such as firewall or access
+// rules, resolved first-match: the rules with the longest prefix of a key
+// apply to it, and among those the one with the highest priority wins, ties
+// going to the rule added first.
+type RuleSet[T any] struct {
+	// tree holds the rules for each prefix, by decreasing priority
+	tree *Tree[[]Rule[T]]
+	size int
+}
+
+// NewRuleSet returns an empty RuleSet.
+func NewRuleSet[T any]() *RuleSet[T] {
+	return &RuleSet[T]{tree: New[[]Rule[T]]()}
+}
+
+// Len returns the number of rules in the set.
+func (s *RuleSet[T]) Len() int {
+	return s.size
+}
+
+// Add returns a new set with the rule added. It goes after the rules with the
+// same prefix and a priority at least as high.
+func (s *RuleSet[T]) Add(r Rule[T]) *RuleSet[T] {
+	old, _ := s.tree.Get(r.Prefix)
+	i := 0
+	for i < len(old) && old[i].Priority >= r.Priority {
+		i++
+	}
+	rules := make([]Rule[T], 0, len(old)+1)
+	rules = append(append(append(rules, old[:i]...), r), old[i:]...)
+	tree, _, _ := s.tree.Insert(r.Prefix, rules)
+	return &RuleSet[T]{tree: tree, size: s.size + 1}
+}
+
+// Remove returns a new set without the first rule for the prefix with the
+// given priority, and whether there was one.
+func (s *RuleSet[T]) Remove(prefix []byte, priority int) (*RuleSet[T], bool) {
+	old, _ := s.tree.Get(prefix)
+	for i, r := range old {
+		if r.Priority != priority {
+			continue
+		}
+		var tree *Tree[[]Rule[T]]
+		if len(old) == 1 {
+			tree, _, _ = s.tree.Delete(prefix)
+		} else {
+			rules := make([]Rule[T], 0, len(old)-1)
+			rules = append(append(rules, old[:i]...), old[i+1:]...)
+			tree, _, _ = s.tree.Insert(prefix, rules)
+		}
+		return &RuleSet[T]{tree: tree, size: s.size - 1}, true
+	}
+	return s, false
+}
+
+// Resolve returns the rule winning for the key, and false if no rule's prefix
+// matches it.
+func (s *RuleSet[T]) Resolve(k []byte) (Rule[T], bool) {
+	_, rules, ok := s.tree.Root().LongestPrefix(k)
+	if !ok {
+		return Rule[T]{}, false
+	}
+	return rules[0], true
+}
+
+// Walk calls fn for each rule, by prefix and then in the order they win.
+// Returning true from fn stops the walk.
+func (s *RuleSet[T]) Walk(fn func(r Rule[T]) bool) {
+	s.tree.Root().Walk(func(_ []byte, rules []Rule[T]) bool {
+		for _, r := range rules {
+			if fn(r) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Shadowed returns the rules that can never win. A longer prefix only
+// overrides a rule for the keys under it, and the prefix itself still
+// resolves to the rule, so a rule is shadowed exactly when a rule for the
+// same prefix wins over it.
+func (s *RuleSet[T]) Shadowed() []Shadowed[T] {
+	var out []Shadowed[T]
+	s.tree.Root().Walk(func(_ []byte, rules []Rule[T]) bool {
+		for _, r := range rules[1:] {
+			out = append(out, Shadowed[T]{Rule: r, By: rules[0]})
+		}
+		return false
+	})
+	return out
+}
@@ -0,0 +1,70 @@
+package iradix
+
+import "testing"
+
+func TestRuleSet(t *testing.T) {
+	s := NewRuleSet[string]()
+	if _, ok := s.Resolve([]byte("10.0.0.1")); ok {
+		t.Fatalf("empty set should not resolve")
+	}
+
+	s = s.Add(Rule[string]{Prefix: []byte(""), Value: "deny"})
+	s = s.Add(Rule[string]{Prefix: []byte("10."), Priority: 1, Value: "allow"})
+	s = s.Add(Rule[string]{Prefix: []byte("10.0."), Priority: 0, Value: "log"})
+	s = s.Add(Rule[string]{Prefix: []byte("10.0."), Priority: 5, Value: "drop"})
+	s = s.Add(Rule[string]{Prefix: []byte("10.0."), Priority: 5, Value: "late"})
+	if s.Len() != 5 {
+		t.Fatalf("bad len: %d", s.Len())
+	}
+
+	cases := map[string]string{
+		"192.168.0.1": "deny",
+		"10.1.2.3":    "allow",
+		"10.0.0.1":    "drop",
+		"10.0.":       "drop",
+		"10":          "deny",
+	}
+	for k, want := range cases {
+		r, ok := s.Resolve([]byte(k))
+		if !ok || r.Value != want {
+			t.Fatalf("%q: got %v %v, want %q", k, r.Value, ok, want)
+		}
+	}
+
+	var order []string
+	s.Walk(func(r Rule[string]) bool {
+		order = append(order, r.Value)
+		return false
+	})
+	if got := len(order); got != 5 || order[2] != "drop" || order[3] != "late" || order[4] != "log" {
+		t.Fatalf("bad walk order: %v", order)
+	}
+
+	shadowed := s.Shadowed()
+	if len(shadowed) != 2 {
+		t.Fatalf("bad shadowed: %v", shadowed)
+	}
+	for i, want := range []string{"late", "log"} {
+		if shadowed[i].Rule.Value != want || shadowed[i].By.Value != "drop" {
+			t.Fatalf("bad shadowed %d: %v", i, shadowed[i])
+		}
+	}
+
+	s2, ok := s.Remove([]byte("10.0."), 5)
+	if !ok || s2.Len() != 4 {
+		t.Fatalf("bad remove: %v %d", ok, s2.Len())
+	}
+	if r, _ := s2.Resolve([]byte("10.0.0.1")); r.Value != "late" {
+		t.Fatalf("bad resolve after remove: %v", r.Value)
+	}
+	if r, _ := s.Resolve([]byte("10.0.0.1")); r.Value != "drop" {
+		t.Fatalf("old set changed: %v", r.Value)
+	}
+	if _, ok := s2.Remove([]byte("10.0."), 7); ok {
+		t.Fatalf("no such rule")
+	}
+	s3, _ := s2.Remove([]byte("10."), 1)
+	if r, _ := s3.Resolve([]byte("10.1.2.3")); r.Value != "deny" {
+		t.Fatalf("bad resolve after removing prefix: %v", r.Value)
+	}
+}
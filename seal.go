@@ -0,0 +1,62 @@
+package iradix
+
+import "errors"
+
+// ErrSealed is returned for a mutation rejected because it falls under a
+// sealed prefix.
+var ErrSealed = errors.New("prefix is sealed")
+
+// SealPrefix marks the keys under the prefix as final: mutations under it are
+// rejected with ErrSealed until UnsealPrefix is called, in this transaction
+// and in the trees committed from it. This is an application level marker,
+// for example for finalizing a completed time bucket while the rest of the
+// tree stays writable.
+func (t *Txn[T]) SealPrefix(prefix []byte) {
+	if t.sealed == nil {
+		t.sealed = New[struct{}]()
+	}
+	t.sealed, _, _ = t.sealed.Insert(prefix, struct{}{})
+}
+
+// UnsealPrefix removes a seal set by SealPrefix for exactly this prefix. It
+// returns false if the prefix wasn't sealed.
+func (t *Txn[T]) UnsealPrefix(prefix []byte) bool {
+	if t.sealed == nil {
+		return false
+	}
+	var ok bool
+	t.sealed, _, ok = t.sealed.Delete(prefix)
+	return ok
+}
+
+// IsSealed returns true if the key is under a sealed prefix.
+func (t *Tree[T]) IsSealed(k []byte) bool {
+	return t.sealed != nil && isSealed(t.sealed, k)
+}
+
+// isSealed returns true if the key is under one of the sealed prefixes.
+func isSealed(sealed *Tree[struct{}], k []byte) bool {
+	_, _, ok := sealed.Root().LongestPrefix(k)
+	return ok
+}
+
+// checkSealed returns ErrSealed, recording it for Err, if the key is under a
+// sealed prefix.
+func (t *Txn[T]) checkSealed(k []byte) error {
+	if t.sealed != nil && isSealed(t.sealed, k) {
+		return t.reject(ErrSealed)
+	}
+	return nil
+}
+
+// checkSealedPrefix returns ErrSealed, recording it for Err, if deleting the
+// keys under the prefix would delete keys under a sealed prefix.
+func (t *Txn[T]) checkSealedPrefix(prefix []byte) error {
+	if t.sealed == nil {
+		return nil
+	}
+	if isSealed(t.sealed, prefix) || t.sealed.Root().prefixNode(prefix) != nil {
+		return t.reject(ErrSealed)
+	}
+	return nil
+}
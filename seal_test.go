@@ -0,0 +1,55 @@
+package iradix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSealPrefix(t *testing.T) {
+	r := New[int]()
+	for _, k := range []string{"2024-01/a", "2024-02/a", "other"} {
+		r, _, _ = r.Insert([]byte(k), 1)
+	}
+
+	txn := r.Txn(false)
+	txn.SealPrefix([]byte("2024-01/"))
+	r = txn.Commit()
+	if !r.IsSealed([]byte("2024-01/a")) || r.IsSealed([]byte("2024-02/a")) {
+		t.Fatalf("bad sealed state")
+	}
+
+	// The seal carries over to later transactions.
+	txn = r.Txn(false)
+	if _, _, err := txn.TryInsert([]byte("2024-01/b"), 2); !errors.Is(err, ErrSealed) {
+		t.Fatalf("bad: %v", err)
+	}
+	if _, _, err := txn.TryDelete([]byte("2024-01/a")); !errors.Is(err, ErrSealed) {
+		t.Fatalf("bad: %v", err)
+	}
+	if txn.DeletePrefix([]byte("2024")) {
+		t.Fatalf("delete prefix covering a sealed prefix should be rejected")
+	}
+	if _, _, err := txn.TryInsert([]byte("2024-02/b"), 2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !errors.Is(txn.Err(), ErrSealed) {
+		t.Fatalf("bad: %v", txn.Err())
+	}
+	r = txn.Commit()
+	if r.Len() != 4 {
+		t.Fatalf("bad len: %d", r.Len())
+	}
+
+	// Unsealing makes the prefix writable again.
+	txn = r.Txn(false)
+	if !txn.UnsealPrefix([]byte("2024-01/")) || txn.UnsealPrefix([]byte("2024-01/")) {
+		t.Fatalf("bad unseal")
+	}
+	if !txn.DeletePrefix([]byte("2024-01/")) {
+		t.Fatalf("delete prefix should be applied")
+	}
+	r = txn.Commit()
+	if r.Len() != 3 || r.IsSealed([]byte("2024-01/a")) {
+		t.Fatalf("bad: %d", r.Len())
+	}
+}
@@ -0,0 +1,96 @@
+package iradix
+
+// SeekFraction is used to seek the iterator to the key roughly f of the way
+// through the keys under its node, f being between 0 and 1, for progress
+// reporting by sampling and for partitioning a scan into balanced parallel
+// parts. It descends the tree once, without visiting the other keys. If the
+// tree was created with WithLeafWeight, the position is chosen by weight, so
+// a weight of 1 for every leaf makes it exact. Otherwise it is chosen by the
+// number of keys kept in every committed node, which makes it exact too. Only
+// the nodes of a transaction in progress have no up to date counts, in which
+// case every child of a node is assumed to hold the same number of keys, which
+// is a rough estimate for skewed trees.
+func (i *Iterator[T]) SeekFraction(f float64) {
+	// As with SeekLowerBound, the stack is built on the way down with the
+	// edges that follow the path taken, and the node is cleared.
+	i.stack = []edges[T]{}
+	n := i.node
+	i.node = nil
+	if n == nil {
+		return
+	}
+	f = min(max(f, 0), 1)
+	if f == 1 {
+		return
+	}
+
+	found := func(n *Node[T]) {
+		i.stack = append(i.stack, edges[T]{edge[T]{node: n}})
+	}
+
+	// measure is what the position is chosen by, if the node has it.
+	var measure func(n *Node[T]) uint64
+	switch {
+	case n.Weight() > 0:
+		measure = func(n *Node[T]) uint64 { return n.Weight() }
+	case n.settled:
+		measure = func(n *Node[T]) uint64 { return uint64(n.leaves) }
+	}
+	if measure != nil {
+		r := uint64(f * float64(measure(n)))
+		for {
+			// The leaf's own share is whatever isn't accounted for by
+			// the children.
+			leafShare := measure(n)
+			for _, e := range n.edges {
+				leafShare -= measure(e.node)
+			}
+			if n.leaf != nil && r < leafShare {
+				found(n)
+				return
+			}
+			r -= leafShare
+
+			idx := -1
+			for j, e := range n.edges {
+				if r < measure(e.node) {
+					idx = j
+					break
+				}
+				r -= measure(e.node)
+			}
+			if idx < 0 {
+				// Can only happen if the counts are stale.
+				return
+			}
+			if idx+1 < len(n.edges) {
+				i.stack = append(i.stack, n.edges[idx+1:])
+			}
+			n = n.edges[idx].node
+		}
+	}
+
+	for {
+		slots := len(n.edges)
+		if n.leaf != nil {
+			slots++
+		}
+		if slots == 0 {
+			return
+		}
+		pos := f * float64(slots)
+		idx := min(int(pos), slots-1)
+		f = pos - float64(idx)
+		if n.leaf != nil {
+			if idx == 0 {
+				found(n)
+				return
+			}
+			idx--
+		}
+		if idx+1 < len(n.edges) {
+			i.stack = append(i.stack, n.edges[idx+1:])
+		}
+		n = n.edges[idx].node
+	}
+}
@@ -0,0 +1,77 @@
+package iradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSeekFraction(t *testing.T) {
+	build := func(opts ...Option[int]) *Tree[int] {
+		txn := New[int](opts...).Txn(false)
+		for i := 0; i < 1000; i++ {
+			txn.Insert([]byte(fmt.Sprintf("%03d", i)), i)
+		}
+		// Skew the tree.
+		for i := 0; i < 1000; i++ {
+			txn.Insert([]byte(fmt.Sprintf("999/%03d", i)), 1000+i)
+		}
+		return txn.Commit()
+	}
+	count := func(it *Iterator[int]) int {
+		n := 0
+		for _, _, ok := it.Next(); ok; _, _, ok = it.Next() {
+			n++
+		}
+		return n
+	}
+
+	// With unit weights the position is exact.
+	r := build(WithLeafWeight(func([]byte, int) uint64 { return 1 }))
+	for _, f := range []float64{0, 0.1, 0.25, 0.5, 0.999, -1} {
+		it := r.Root().Iterator()
+		it.SeekFraction(f)
+		want := 2000 - int(max(f, 0)*2000)
+		if got := count(it); got != want {
+			t.Fatalf("f=%v: %d keys left, want %d", f, got, want)
+		}
+	}
+	for _, f := range []float64{1, 2} {
+		it := r.Root().Iterator()
+		it.SeekFraction(f)
+		if got := count(it); got != 0 {
+			t.Fatalf("f=%v: %d keys left", f, got)
+		}
+	}
+
+	// Without weights the position is exact too, using the key counts.
+	r = build()
+	for _, f := range []float64{0, 0.1, 0.25, 0.5, 0.999} {
+		it := r.Root().Iterator()
+		it.SeekFraction(f)
+		want := 2000 - int(f*2000)
+		if got := count(it); got != want {
+			t.Fatalf("f=%v: %d keys left, want %d", f, got, want)
+		}
+	}
+
+	// In a transaction in progress it's an estimate, but it stays ordered
+	// and in range.
+	txn := r.Txn(false)
+	txn.Insert([]byte("000/x"), -1)
+	prev := 2002
+	for _, f := range []float64{0, 0.1, 0.3, 0.5, 0.7, 0.9, 0.99} {
+		it := txn.Root().Iterator()
+		it.SeekFraction(f)
+		got := count(it)
+		if got > prev || got == 0 {
+			t.Fatalf("f=%v: %d keys left after %d", f, got, prev)
+		}
+		prev = got
+	}
+
+	it := New[int]().Root().Iterator()
+	it.SeekFraction(0.5)
+	if count(it) != 0 {
+		t.Fatalf("empty tree")
+	}
+}
@@ -0,0 +1,86 @@
+package iradix
+
+// Set is an immutable ordered set of byte string keys. It is a Tree with
+// empty struct values, which take no space in the leaves, for users who only
+// need membership and ordering.
+type Set struct {
+	tree *Tree[struct{}]
+}
+
+// NewSet returns an empty Set.
+func NewSet() *Set {
+	return &Set{tree: New[struct{}]()}
+}
+
+// Len returns the number of keys in the set.
+func (s *Set) Len() int {
+	return s.tree.Len()
+}
+
+// Contains returns true if the key is in the set.
+func (s *Set) Contains(k []byte) bool {
+	_, ok := s.tree.Get(k)
+	return ok
+}
+
+// Add returns a new set with the key added, and whether it was already
+// present.
+func (s *Set) Add(k []byte) (*Set, bool) {
+	tree, _, ok := s.tree.Insert(k, struct{}{})
+	return &Set{tree: tree}, ok
+}
+
+// Remove returns a new set with the key removed, and whether it was present.
+func (s *Set) Remove(k []byte) (*Set, bool) {
+	tree, _, ok := s.tree.Delete(k)
+	if !ok {
+		return s, false
+	}
+	return &Set{tree: tree}, true
+}
+
+// Walk calls fn for each key in order. Returning true from fn stops the walk.
+func (s *Set) Walk(fn func(k []byte) bool) {
+	s.tree.Root().Walk(func(k []byte, _ struct{}) bool {
+		return fn(k)
+	})
+}
+
+// WalkPrefix calls fn for each key under the prefix in order. Returning true
+// from fn stops the walk.
+func (s *Set) WalkPrefix(prefix []byte, fn func(k []byte) bool) {
+	s.tree.Root().WalkPrefix(prefix, func(k []byte, _ struct{}) bool {
+		return fn(k)
+	})
+}
+
+// Tree returns the underlying tree, which can be used for the richer query
+// operations of Node.
+func (s *Set) Tree() *Tree[struct{}] {
+	return s.tree
+}
+
+// UnionSets returns a set holding the keys of all the given sets. The largest
+// set is used as the base, so only the keys of the others are inserted.
+func UnionSets(sets ...*Set) *Set {
+	if len(sets) == 0 {
+		return NewSet()
+	}
+	base := sets[0]
+	for _, s := range sets[1:] {
+		if s.Len() > base.Len() {
+			base = s
+		}
+	}
+	txn := base.tree.Txn(false)
+	for _, s := range sets {
+		if s == base {
+			continue
+		}
+		s.Walk(func(k []byte) bool {
+			txn.Insert(k, struct{}{})
+			return false
+		})
+	}
+	return &Set{tree: txn.Commit()}
+}
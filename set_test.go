@@ -0,0 +1,67 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func setKeys(s *Set) []string {
+	var out []string
+	s.Walk(func(k []byte) bool {
+		out = append(out, string(k))
+		return false
+	})
+	return out
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet()
+	s, _ = s.Add([]byte("foo"))
+	s, _ = s.Add([]byte("bar"))
+	s2, existed := s.Add([]byte("foo"))
+	if !existed {
+		t.Fatalf("foo should exist")
+	}
+	if s2.Len() != 2 || !s2.Contains([]byte("bar")) || s2.Contains([]byte("baz")) {
+		t.Fatalf("bad set: %v", setKeys(s2))
+	}
+
+	s3, ok := s2.Remove([]byte("bar"))
+	if !ok || s3.Contains([]byte("bar")) || !s2.Contains([]byte("bar")) {
+		t.Fatalf("bad remove")
+	}
+	if _, ok := s3.Remove([]byte("bar")); ok {
+		t.Fatalf("bar should be gone")
+	}
+
+	var prefixed []string
+	s2.WalkPrefix([]byte("f"), func(k []byte) bool {
+		prefixed = append(prefixed, string(k))
+		return false
+	})
+	if !slices.Equal(prefixed, []string{"foo"}) {
+		t.Fatalf("bad: %v", prefixed)
+	}
+}
+
+func TestUnionSets(t *testing.T) {
+	a, b, c := NewSet(), NewSet(), NewSet()
+	for _, k := range []string{"a", "b", "c"} {
+		a, _ = a.Add([]byte(k))
+	}
+	for _, k := range []string{"b", "d"} {
+		b, _ = b.Add([]byte(k))
+	}
+	c, _ = c.Add([]byte("e"))
+
+	u := UnionSets(a, b, c)
+	if got := setKeys(u); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Fatalf("bad: %v", got)
+	}
+	if a.Len() != 3 || b.Len() != 2 || c.Len() != 1 {
+		t.Fatalf("inputs modified")
+	}
+	if UnionSets().Len() != 0 {
+		t.Fatalf("empty union should be empty")
+	}
+}
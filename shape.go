@@ -0,0 +1,82 @@
+package iradix
+
+import "unsafe"
+
+// Shape summarizes the structure of the tree under a node, see Node.Shape.
+type Shape struct {
+	// Nodes is the number of nodes and Leaves the number of keys.
+	Nodes  int
+	Leaves int
+
+	// MaxDepth is the number of edges on the longest path from the node.
+	MaxDepth int
+
+	// Fanout maps a number of edges to the number of nodes having it.
+	Fanout map[int]int
+
+	// Bytes estimates the memory used by the nodes, edges, prefixes, leaves
+	// and keys. The memory referenced by the values isn't counted.
+	Bytes int64
+}
+
+// Shape returns a summary of the structure of the tree under the node, which
+// takes a walk over all of its nodes.
+func (n *Node[T]) Shape() Shape {
+	var (
+		nodeSize = int64(unsafe.Sizeof(Node[T]{}))
+		edgeSize = int64(unsafe.Sizeof(edge[T]{}))
+		leafSize = int64(unsafe.Sizeof(leafNode[T]{}))
+	)
+	s := Shape{Fanout: make(map[int]int)}
+	for it := n.rawIterator(); it.Front() != nil; it.Next() {
+		elem := it.Front()
+		s.Nodes++
+		s.MaxDepth = max(s.MaxDepth, it.depth)
+		s.Fanout[len(elem.edges)]++
+		s.Bytes += nodeSize + int64(cap(elem.edges))*edgeSize + int64(len(elem.prefix))
+		if elem.leaf != nil {
+			s.Leaves++
+			s.Bytes += leafSize + int64(len(elem.leaf.key))
+		}
+	}
+	return s
+}
+
+// ShapeDiff reports how the structure of a tree changed, see CompareShapes.
+// The deltas are the new value minus the old one.
+type ShapeDiff struct {
+	Old, New Shape
+
+	Nodes    int
+	Leaves   int
+	MaxDepth int
+	Bytes    int64
+
+	// Fanout holds the change in the number of nodes for each number of
+	// edges, leaving out the ones that didn't change.
+	Fanout map[int]int
+}
+
+// CompareShapes compares the structure of the trees under two nodes, such as
+// the same data built before and after a change of key encoding, to track the
+// effect of such changes on memory across releases. The trees don't need to
+// be related.
+func CompareShapes[T any](a, b *Node[T]) ShapeDiff {
+	d := ShapeDiff{Old: a.Shape(), New: b.Shape(), Fanout: make(map[int]int)}
+	d.Nodes = d.New.Nodes - d.Old.Nodes
+	d.Leaves = d.New.Leaves - d.Old.Leaves
+	d.MaxDepth = d.New.MaxDepth - d.Old.MaxDepth
+	d.Bytes = d.New.Bytes - d.Old.Bytes
+	for fanout, count := range d.New.Fanout {
+		d.Fanout[fanout] += count
+	}
+	for fanout, count := range d.Old.Fanout {
+		d.Fanout[fanout] -= count
+	}
+	for fanout, delta := range d.Fanout {
+		if delta == 0 {
+			delete(d.Fanout, fanout)
+		}
+	}
+	return d
+}
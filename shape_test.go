@@ -0,0 +1,43 @@
+package iradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShape(t *testing.T) {
+	a := New[int]()
+	for i, k := range []string{"foo", "foobar", "foobaz", "zip"} {
+		a, _, _ = a.Insert([]byte(k), i)
+	}
+
+	// root -> foo (leaf) -> ba -> r, z
+	//      -> zip
+	s := a.Root().Shape()
+	if s.Nodes != 6 || s.Leaves != 4 || s.MaxDepth != 3 {
+		t.Fatalf("bad shape: %+v", s)
+	}
+	if want := map[int]int{0: 3, 1: 1, 2: 2}; !reflect.DeepEqual(s.Fanout, want) {
+		t.Fatalf("bad fanout: %v", s.Fanout)
+	}
+	if s.Bytes <= 0 {
+		t.Fatalf("bad bytes: %d", s.Bytes)
+	}
+
+	b, _, _ := a.Insert([]byte("foobarqux"), 4)
+	b, _, _ = b.Delete([]byte("zip"))
+	d := CompareShapes(a.Root(), b.Root())
+	if d.Nodes != 0 || d.Leaves != 0 || d.MaxDepth != 1 {
+		t.Fatalf("bad diff: %+v", d)
+	}
+	if want := map[int]int{1: 2, 2: -1, 0: -1}; !reflect.DeepEqual(d.Fanout, want) {
+		t.Fatalf("bad fanout diff: %v", d.Fanout)
+	}
+	if d.Bytes != d.New.Bytes-d.Old.Bytes {
+		t.Fatalf("bad bytes diff: %d", d.Bytes)
+	}
+
+	if d := CompareShapes(a.Root(), a.Root()); d.Nodes != 0 || d.Bytes != 0 || len(d.Fanout) != 0 {
+		t.Fatalf("same tree should not differ: %+v", d)
+	}
+}
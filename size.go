@@ -0,0 +1,45 @@
+package iradix
+
+// Sizer is used to compute the size in bytes of a value, for memory accounting.
+type Sizer[T any] func(v T) int
+
+// WithSizer enables value size accounting. Every node keeps the total size of
+// the values below it, which is updated for the modified nodes when a
+// transaction is committed, so the size of any prefix can be found without
+// visiting its leaves.
+func WithSizer[T any](fn Sizer[T]) Option[T] {
+	return func(c *config[T]) {
+		c.sizer = fn
+	}
+}
+
+// updateSize computes the size of the node from its leaf and the sizes of its
+// children, which must already be up to date.
+func (n *Node[T]) updateSize(fn Sizer[T]) {
+	var size int64
+	if n.leaf != nil {
+		size = int64(fn(n.leaf.val))
+	}
+	for _, e := range n.edges {
+		size += e.node.Size()
+	}
+	n.agg.size = size
+}
+
+// Size returns the total size of the values under this node. It is zero if the
+// tree was not created with WithSizer.
+func (n *Node[T]) Size() int64 {
+	if n.agg == nil {
+		return 0
+	}
+	return n.agg.size
+}
+
+// SizePrefix returns the total size of the values under the given prefix. It is
+// zero if the tree was not created with WithSizer.
+func (n *Node[T]) SizePrefix(prefix []byte) int64 {
+	if n = n.prefixNode(prefix); n == nil {
+		return 0
+	}
+	return n.Size()
+}
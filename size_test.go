@@ -0,0 +1,38 @@
+package iradix
+
+import "testing"
+
+func TestSizePrefix(t *testing.T) {
+	r := New[string](WithSizer(func(v string) int { return len(v) }))
+	txn := r.Txn(false)
+	txn.Insert([]byte("tenant1/a"), "12345")
+	txn.Insert([]byte("tenant1/b"), "123")
+	txn.Insert([]byte("tenant2/a"), "1234567890")
+	txn.Insert([]byte("tenant2"), "1")
+	r = txn.Commit()
+
+	cases := map[string]int64{
+		"":          19,
+		"tenant":    19,
+		"tenant1":   8,
+		"tenant1/":  8,
+		"tenant1/b": 3,
+		"tenant2":   11,
+		"tenant3":   0,
+		"x":         0,
+	}
+	for p, want := range cases {
+		if got := r.Root().SizePrefix([]byte(p)); got != want {
+			t.Fatalf("prefix %q: got %d want %d", p, got, want)
+		}
+	}
+
+	r2, _, _ := r.Insert([]byte("tenant1/a"), "")
+	r2, _ = r2.DeletePrefix([]byte("tenant2/"))
+	if got := r2.Root().Size(); got != 4 {
+		t.Fatalf("bad size: %d", got)
+	}
+	if got := r.Root().Size(); got != 19 {
+		t.Fatalf("old tree size changed: %d", got)
+	}
+}
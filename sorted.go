@@ -0,0 +1,69 @@
+package iradix
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// VerifySorted checks the structural invariants of the tree under root that
+// ordered iteration relies on: edges are sorted by label, every label is the
+// first byte of its child's prefix, every leaf key is the path leading to it,
+// and so the keys are visited in strictly increasing order. It returns an error
+// describing the first violation found.
+func VerifySorted[T any](root *Node[T]) error {
+	var last []byte
+	first := true
+	var verify func(n *Node[T], path []byte) error
+	verify = func(n *Node[T], path []byte) error {
+		if n.leaf != nil {
+			if !bytes.Equal(n.leaf.key, path) {
+				return fmt.Errorf("leaf key %q stored at path %q", n.leaf.key, path)
+			}
+			if !first && bytes.Compare(last, n.leaf.key) >= 0 {
+				return fmt.Errorf("key %q visited after %q", n.leaf.key, last)
+			}
+			last, first = n.leaf.key, false
+		}
+		for i, e := range n.edges {
+			if len(e.node.prefix) == 0 || e.node.prefix[0] != e.label {
+				return fmt.Errorf("edge %q at path %q leads to prefix %q", e.label, path, e.node.prefix)
+			}
+			if i > 0 && n.edges[i-1].label >= e.label {
+				return fmt.Errorf("edges at path %q are not sorted", path)
+			}
+			if err := verify(e.node, concat(path, e.node.prefix)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return verify(root, root.prefix)
+}
+
+// IsSortedSubset returns true if every key yielded by other is in the tree under
+// root, and the keys are yielded in strictly increasing order. It consumes both
+// sides as streams, so it uses constant memory. The type of other matches
+// iter.Seq2[[]byte, T], so iterators can be passed directly.
+func IsSortedSubset[T any](root *Node[T], other func(yield func([]byte, T) bool)) bool {
+	it := root.Iterator()
+	treeKey, _, treeOk := it.Next()
+	var last []byte
+	first := true
+	result := true
+	other(func(k []byte, _ T) bool {
+		if !first && bytes.Compare(last, k) >= 0 {
+			result = false
+			return false
+		}
+		last, first = k, false
+		for treeOk && bytes.Compare(treeKey, k) < 0 {
+			treeKey, _, treeOk = it.Next()
+		}
+		if !treeOk || !bytes.Equal(treeKey, k) {
+			result = false
+			return false
+		}
+		return true
+	})
+	return result
+}
@@ -0,0 +1,72 @@
+package iradix
+
+import "testing"
+
+func TestVerifySorted(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"", "foo", "foo/bar", "foobar", "zip", "zap"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	if err := VerifySorted(r.Root()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := VerifySorted(New[int]().Root()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Break the edge order in a copy.
+	c := CopyTree(r)
+	c.root.edges[0], c.root.edges[1] = c.root.edges[1], c.root.edges[0]
+	if err := VerifySorted(c.Root()); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	// Break a leaf key in a copy.
+	c = CopyTree(r)
+	_, n := c.root.getEdge('z')
+	n.edges[0].node.leaf.key = []byte("zzz")
+	if err := VerifySorted(c.Root()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestIsSortedSubset(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a", "b", "c", "d", "e"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	seq := func(keys ...string) func(yield func([]byte, int) bool) {
+		return func(yield func([]byte, int) bool) {
+			for _, k := range keys {
+				if !yield([]byte(k), 0) {
+					return
+				}
+			}
+		}
+	}
+
+	cases := []struct {
+		keys   []string
+		expect bool
+	}{
+		{nil, true},
+		{[]string{"a", "b", "c", "d", "e"}, true},
+		{[]string{"b", "d"}, true},
+		{[]string{"d", "b"}, false},
+		{[]string{"b", "b"}, false},
+		{[]string{"b", "bb"}, false},
+		{[]string{"e", "f"}, false},
+	}
+	for _, tc := range cases {
+		if got := IsSortedSubset(r.Root(), seq(tc.keys...)); got != tc.expect {
+			t.Fatalf("%v: got %v", tc.keys, got)
+		}
+	}
+
+	// The tree's own iteration is a sorted subset of itself.
+	if !IsSortedSubset(r.Root(), func(yield func([]byte, int) bool) {
+		r.Root().Walk(func(k []byte, v int) bool { return !yield(k, v) })
+	}) {
+		t.Fatalf("tree is not a sorted subset of itself")
+	}
+}
@@ -0,0 +1,58 @@
+package iradix
+
+import "bytes"
+
+// SplitIterators partitions the keys under the node into the given number of
+// contiguous ranges, returning an iterator over each in key order, so that
+// workers can scan disjoint sections of the tree concurrently without
+// coordinating. The boundaries are found from the number of keys kept in every
+// committed node, like SplitPoints, so the ranges hold the same number of keys
+// give or take one, and are only empty if there are fewer keys than ranges.
+// The nodes of a transaction in progress have no up to date counts, so their
+// boundaries are only estimated, see SeekFraction. Seeking one of the
+// iterators keeps the end of its range.
+func (n *Node[T]) SplitIterators(parts int) []*Iterator[T] {
+	if parts <= 0 {
+		return nil
+	}
+
+	// bounds[j] is the first key of part j+1, or nil if there is none left.
+	bounds := make([][]byte, parts-1)
+	for j := range bounds {
+		var k []byte
+		if n.settled {
+			if k = n.keyAt((j + 1) * n.leaves / parts); k == nil {
+				break
+			}
+		} else {
+			it := n.Iterator()
+			it.SeekFraction(float64(j+1) / float64(parts))
+			var ok bool
+			if k, _, ok = it.Next(); !ok {
+				break
+			}
+		}
+		if j > 0 && bytes.Compare(k, bounds[j-1]) < 0 {
+			k = bounds[j-1]
+		}
+		bounds[j] = k
+	}
+
+	iters := make([]*Iterator[T], parts)
+	for j := range iters {
+		it := n.Iterator()
+		if j > 0 {
+			if bounds[j-1] == nil {
+				// The previous parts cover all the keys.
+				iters[j] = &Iterator[T]{}
+				continue
+			}
+			it.SeekLowerBound(bounds[j-1])
+		}
+		if j < len(bounds) {
+			it.end = bounds[j]
+		}
+		iters[j] = it
+	}
+	return iters
+}
@@ -0,0 +1,55 @@
+package iradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplitIterators(t *testing.T) {
+	for _, weighted := range []bool{false, true} {
+		var opts []Option[int]
+		if weighted {
+			opts = append(opts, WithLeafWeight[int](func([]byte, int) uint64 { return 1 }))
+		}
+		txn := New[int](opts...).Txn(false)
+		for i := 0; i < 1000; i++ {
+			txn.Insert([]byte(fmt.Sprintf("%03x", i*7)), i)
+		}
+		r := txn.Commit()
+
+		for _, parts := range []int{1, 3, 7, 8, 999, 1000, 2000} {
+			iters := r.Root().SplitIterators(parts)
+			if len(iters) != parts {
+				t.Fatalf("bad number of iterators: %d", len(iters))
+			}
+			var all []string
+			for _, it := range iters {
+				var keys []string
+				for k, _, ok := it.Next(); ok; k, _, ok = it.Next() {
+					keys = append(keys, string(k))
+				}
+				if parts <= 1000 && len(keys) != 1000/parts && len(keys) != 1000/parts+1 {
+					t.Fatalf("%d parts: unbalanced part of %d keys", parts, len(keys))
+				}
+				if parts > 1000 && len(keys) > 1 {
+					t.Fatalf("%d parts: part of %d keys", parts, len(keys))
+				}
+				all = append(all, keys...)
+			}
+			if len(all) != 1000 {
+				t.Fatalf("%d parts: got %d keys", parts, len(all))
+			}
+			for i := 1; i < len(all); i++ {
+				if all[i-1] >= all[i] {
+					t.Fatalf("%d parts: keys out of order or overlapping: %q %q", parts, all[i-1], all[i])
+				}
+			}
+		}
+	}
+
+	if iters := New[int]().Root().SplitIterators(4); len(iters) != 4 {
+		t.Fatalf("bad: %d", len(iters))
+	} else if _, _, ok := iters[0].Next(); ok {
+		t.Fatalf("empty tree should have no keys")
+	}
+}
@@ -0,0 +1,66 @@
+package iradix
+
+// SplitPoints suggests the boundary keys splitting the tree into n parts with
+// roughly the same number of keys, for balancing parallel work or shards. It
+// returns up to n-1 keys in order; part i holds the keys from the boundary
+// before it (or the start of the tree) up to, but excluding, the boundary
+// after it. Fewer boundaries are returned if the tree has fewer than n keys.
+// Each boundary is found by descending the tree once, using the number of
+// keys kept in every node, so it takes O(depth × fanout) rather than a walk
+// over the keys.
+func (t *Tree[T]) SplitPoints(n int) [][]byte {
+	if n <= 1 || t.size == 0 {
+		return nil
+	}
+	if n > t.size {
+		n = t.size
+	}
+
+	points := make([][]byte, 0, n-1)
+	for i := 1; i < n; i++ {
+		// The boundary is the first key of the next part.
+		points = append(points, t.root.keyAt(i*t.size/n))
+	}
+	return points
+}
+
+// updateLeaves counts the leaves under a node from the counts of its
+// children.
+func (n *Node[T]) updateLeaves() {
+	var count int
+	if n.leaf != nil {
+		count = 1
+	}
+	for _, e := range n.edges {
+		count += e.node.leaves
+	}
+	n.leaves = count
+}
+
+// keyAt returns the key at the given position in key order under a settled
+// node, or nil if there are not that many keys.
+func (n *Node[T]) keyAt(i int) []byte {
+	if i < 0 || i >= n.leaves {
+		return nil
+	}
+	for {
+		if n.leaf != nil {
+			if i == 0 {
+				return n.leaf.key
+			}
+			i--
+		}
+		next := n
+		for _, e := range n.edges {
+			if i < e.node.leaves {
+				next = e.node
+				break
+			}
+			i -= e.node.leaves
+		}
+		if next == n {
+			return nil
+		}
+		n = next
+	}
+}
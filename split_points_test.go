@@ -0,0 +1,73 @@
+package iradix
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSplitPoints(t *testing.T) {
+	r := New[int]()
+	txn := r.Txn(false)
+	for i := 0; i < 1000; i++ {
+		txn.Insert([]byte(fmt.Sprintf("key/%04d", i)), i)
+	}
+	r = txn.Commit()
+
+	for _, n := range []int{2, 3, 7, 10} {
+		points := r.SplitPoints(n)
+		if len(points) != n-1 {
+			t.Fatalf("%d: bad number of points: %d", n, len(points))
+		}
+		// Count the keys in every part.
+		counts := make([]int, n)
+		r.Root().Walk(func(k []byte, _ int) bool {
+			part := 0
+			for part < len(points) && bytes.Compare(k, points[part]) >= 0 {
+				part++
+			}
+			counts[part]++
+			return false
+		})
+		for _, c := range counts {
+			if c < 1000/n || c > 1000/n+1 {
+				t.Fatalf("%d: unbalanced parts: %v", n, counts)
+			}
+		}
+	}
+
+	// The counts the boundaries are found with follow later transactions.
+	txn = r.Txn(false)
+	txn.DeletePrefix([]byte("key/01"))
+	for i := 0; i < 1000; i += 7 {
+		txn.Delete([]byte(fmt.Sprintf("key/%04d", i)))
+	}
+	txn.Insert([]byte("key"), -1)
+	txn.Insert([]byte("key/0500/x"), -2)
+	r2 := txn.Commit()
+	var keys [][]byte
+	r2.Root().Walk(func(k []byte, _ int) bool {
+		keys = append(keys, k)
+		return false
+	})
+	for _, n := range []int{2, 5, 13} {
+		points := r2.SplitPoints(n)
+		for i, p := range points {
+			if want := keys[(i+1)*len(keys)/n]; !bytes.Equal(p, want) {
+				t.Fatalf("%d: bad point %d: %q, expected %q", n, i, p, want)
+			}
+		}
+	}
+	if points := r.SplitPoints(2); string(points[0]) != "key/0500" {
+		t.Fatalf("original tree changed: %q", points)
+	}
+
+	small, _, _ := New[int]().Insert([]byte("a"), 1)
+	small, _, _ = small.Insert([]byte("b"), 2)
+	if points := small.SplitPoints(5); len(points) != 1 || string(points[0]) != "b" {
+		t.Fatalf("bad: %q", points)
+	}
+	if points := New[int]().SplitPoints(3); points != nil {
+		t.Fatalf("bad: %q", points)
+	}
+}
@@ -0,0 +1,58 @@
+package iradix
+
+// StructNode is a node of the tree as seen by a StructIterator. Its fields are
+// part of the stable API, so external serializers and analyzers can rely on
+// them across versions.
+type StructNode[T any] struct {
+	// Prefix is the full path accumulated from the starting node down to
+	// this node, which is the key of its leaf if it has one.
+	Prefix []byte
+
+	// Segment is the part of the prefix contributed by this node's edge.
+	Segment []byte
+
+	// Depth is the number of edges between the starting node and this one.
+	Depth int
+
+	// Edges is the number of children of the node.
+	Edges int
+
+	// HasLeaf is true if the node has a leaf, whose value is Value.
+	HasLeaf bool
+	Value   T
+}
+
+// StructIterator visits every node of a tree in pre-order, including the
+// internal nodes without a leaf, exposing the structure of the tree rather
+// than only its entries. A node's children are visited in key order, right
+// after the node itself.
+type StructIterator[T any] struct {
+	raw *rawIterator[T]
+}
+
+// StructIterator is used to return a structural iterator at the given node to
+// walk the tree, see StructIterator.
+func (n *Node[T]) StructIterator() *StructIterator[T] {
+	return &StructIterator[T]{raw: n.rawIterator()}
+}
+
+// Next returns the next node, and false once the iteration is done. The
+// prefix and segment are copies that may be retained.
+func (i *StructIterator[T]) Next() (StructNode[T], bool) {
+	n := i.raw.Front()
+	if n == nil {
+		return StructNode[T]{}, false
+	}
+	s := StructNode[T]{
+		Prefix:  []byte(i.raw.Path()),
+		Segment: append([]byte(nil), n.prefix...),
+		Depth:   i.raw.depth,
+		Edges:   len(n.edges),
+	}
+	if n.leaf != nil {
+		s.HasLeaf = true
+		s.Value = n.leaf.val
+	}
+	i.raw.Next()
+	return s, true
+}
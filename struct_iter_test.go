@@ -0,0 +1,40 @@
+package iradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStructIterator(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"foo", "foobar", "foobaz", "zip"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	type node struct {
+		prefix, segment string
+		depth, edges    int
+		hasLeaf         bool
+		value           int
+	}
+	var got []node
+	it := r.Root().StructIterator()
+	for n, ok := it.Next(); ok; n, ok = it.Next() {
+		got = append(got, node{string(n.Prefix), string(n.Segment), n.Depth, n.Edges, n.HasLeaf, n.Value})
+	}
+	want := []node{
+		{"", "", 0, 2, false, 0},
+		{"foo", "foo", 1, 1, true, 0},
+		{"fooba", "ba", 2, 2, false, 0},
+		{"foobar", "r", 3, 0, true, 1},
+		{"foobaz", "z", 3, 0, true, 2},
+		{"zip", "zip", 1, 0, true, 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	if _, ok := New[int]().Root().StructIterator().Next(); !ok {
+		t.Fatalf("the empty root should be visited")
+	}
+}
@@ -0,0 +1,34 @@
+package iradix
+
+// WithSuffixIndex makes the tree maintain a secondary index of its keys
+// reversed, kept in sync by its transactions, so that the keys ending with a
+// given suffix can be found with WalkSuffix. The index costs a second tree
+// holding every key.
+func WithSuffixIndex[T any]() Option[T] {
+	return func(c *config[T]) {
+		c.suffixIndex = true
+	}
+}
+
+// reverseKey returns a reversed copy of the key.
+func reverseKey(k []byte) []byte {
+	r := make([]byte, len(k))
+	for i, b := range k {
+		r[len(k)-1-i] = b
+	}
+	return r
+}
+
+// WalkSuffix is used to walk the keys of the tree ending with the given
+// suffix. The keys are visited in the order of their reversed bytes. It visits
+// nothing unless the tree was created with WithSuffixIndex.
+func (t *Tree[T]) WalkSuffix(suffix []byte, fn WalkFn[T]) {
+	if t.suffix == nil {
+		return
+	}
+	t.suffix.Root().WalkPrefix(reverseKey(suffix), func(rk []byte, _ struct{}) bool {
+		k := reverseKey(rk)
+		v, _ := t.root.Get(k)
+		return fn(k, v)
+	})
+}
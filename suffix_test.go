@@ -0,0 +1,53 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWalkSuffix(t *testing.T) {
+	r := New[int](WithSuffixIndex[int]())
+	txn := r.Txn(false)
+	for i, k := range []string{"a.example.com", "b.example.com", "example.org", "c.test.com", "com"} {
+		txn.Insert([]byte(k), i)
+	}
+	r = txn.Commit()
+
+	suffix := func(r *Tree[int], s string) []string {
+		var out []string
+		r.WalkSuffix([]byte(s), func(k []byte, v int) bool {
+			out = append(out, string(k))
+			return false
+		})
+		slices.Sort(out)
+		return out
+	}
+	if got := suffix(r, ".example.com"); !slices.Equal(got, []string{"a.example.com", "b.example.com"}) {
+		t.Fatalf("bad: %v", got)
+	}
+	if got := suffix(r, "com"); len(got) != 4 {
+		t.Fatalf("bad: %v", got)
+	}
+
+	// The index follows deletes, including prefix deletes.
+	txn = r.Txn(false)
+	txn.Delete([]byte("a.example.com"))
+	txn.DeletePrefix([]byte("c."))
+	r2 := txn.Commit()
+	if got := suffix(r2, "com"); !slices.Equal(got, []string{"b.example.com", "com"}) {
+		t.Fatalf("bad: %v", got)
+	}
+	if got := suffix(r, "com"); len(got) != 4 {
+		t.Fatalf("old tree should keep its index: %v", got)
+	}
+
+	// Values come from the tree.
+	var v int
+	r2.WalkSuffix([]byte(".org"), func(_ []byte, val int) bool {
+		v = val
+		return true
+	})
+	if v != 2 {
+		t.Fatalf("bad value: %d", v)
+	}
+}
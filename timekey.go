@@ -0,0 +1,62 @@
+package iradix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+// timeKeyLen is the length of the timestamp appended by AppendTimeKey.
+const timeKeyLen = 8
+
+// AppendTimeKey appends a timestamp to the given prefix so that keys sharing
+// the prefix sort from the newest to the oldest timestamp, which makes "latest
+// entries first" scans a plain forward iteration. The timestamp is a fixed
+// width encoding of the nanoseconds since the Unix epoch, so more bytes (such
+// as an ID to make keys unique) can follow it without breaking the ordering.
+func AppendTimeKey(prefix []byte, t time.Time) []byte {
+	// Flipping the sign bit makes the two's complement value sort as an
+	// unsigned integer, and inverting everything reverses the order.
+	enc := ^(uint64(t.UnixNano()) ^ (1 << 63))
+	return binary.BigEndian.AppendUint64(prefix, enc)
+}
+
+// ParseTimeKey returns the timestamp encoded by AppendTimeKey right after the
+// given prefix in the key, and false if the key doesn't have one.
+func ParseTimeKey(key, prefix []byte) (time.Time, bool) {
+	if !bytes.HasPrefix(key, prefix) || len(key) < len(prefix)+timeKeyLen {
+		return time.Time{}, false
+	}
+	enc := binary.BigEndian.Uint64(key[len(prefix):])
+	return time.Unix(0, int64(^enc^(1<<63))), true
+}
+
+// WalkTimeRange walks the keys made by AppendTimeKey with the given prefix
+// whose timestamp is in [from, to), from the newest to the oldest. Keys under
+// the prefix that are too short to hold a timestamp are skipped.
+func (n *Node[T]) WalkTimeRange(prefix []byte, from, to time.Time, fn WalkFn[T]) {
+	if !from.Before(to) {
+		return
+	}
+
+	// The newest timestamp in range has the smallest key.
+	lower := AppendTimeKey(append([]byte(nil), prefix...), to.Add(-time.Nanosecond))
+	upper := AppendTimeKey(append([]byte(nil), prefix...), from)
+
+	it := n.Iterator()
+	it.SeekLowerBound(lower)
+	for key, val, ok := it.Next(); ok; key, val, ok = it.Next() {
+		if !bytes.HasPrefix(key, prefix) {
+			return
+		}
+		if len(key) < len(upper) {
+			continue
+		}
+		if bytes.Compare(key[:len(upper)], upper) > 0 {
+			return
+		}
+		if fn(key, val) {
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package iradix
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeKey(t *testing.T) {
+	base := time.Date(2024, 6, 1, 14, 2, 0, 0, time.UTC)
+	times := []time.Time{
+		time.Unix(0, 0).Add(-time.Hour),
+		time.Unix(0, 0),
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(time.Minute),
+	}
+
+	// Later times sort first.
+	for i := 1; i < len(times); i++ {
+		a := AppendTimeKey([]byte("p/"), times[i-1])
+		b := AppendTimeKey([]byte("p/"), times[i])
+		if bytes.Compare(b, a) >= 0 {
+			t.Fatalf("%v should sort before %v", times[i], times[i-1])
+		}
+	}
+	for _, ts := range times {
+		key := append(AppendTimeKey([]byte("p/"), ts), "id"...)
+		got, ok := ParseTimeKey(key, []byte("p/"))
+		if !ok || !got.Equal(ts) {
+			t.Fatalf("bad parse: %v %v", got, ok)
+		}
+	}
+	if _, ok := ParseTimeKey([]byte("p/abc"), []byte("p/")); ok {
+		t.Fatalf("parsed a short key")
+	}
+}
+
+func TestWalkTimeRange(t *testing.T) {
+	base := time.Date(2024, 6, 1, 14, 0, 0, 0, time.UTC)
+	r := New[int]()
+	txn := r.Txn(false)
+	for i := 0; i < 10; i++ {
+		txn.Insert(AppendTimeKey([]byte("cpu/"), base.Add(time.Duration(i)*time.Minute)), i)
+		txn.Insert(AppendTimeKey([]byte("mem/"), base.Add(time.Duration(i)*time.Minute)), 100+i)
+	}
+	txn.Insert([]byte("cpu/x"), -1)
+	r = txn.Commit()
+
+	walk := func(prefix string, from, to time.Time) []int {
+		var out []int
+		r.Root().WalkTimeRange([]byte(prefix), from, to, func(k []byte, v int) bool {
+			out = append(out, v)
+			return false
+		})
+		return out
+	}
+
+	got := walk("cpu/", base.Add(2*time.Minute), base.Add(5*time.Minute))
+	if !reflect.DeepEqual(got, []int{4, 3, 2}) {
+		t.Fatalf("bad: %v", got)
+	}
+	got = walk("mem/", base.Add(-time.Hour), base.Add(time.Hour))
+	if len(got) != 10 || got[0] != 109 || got[9] != 100 {
+		t.Fatalf("bad: %v", got)
+	}
+	if got := walk("cpu/", base.Add(time.Hour), base.Add(2*time.Hour)); got != nil {
+		t.Fatalf("bad: %v", got)
+	}
+	if got := walk("cpu/", base.Add(5*time.Minute), base); got != nil {
+		t.Fatalf("bad: %v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package iradix
+
+// Tombstone is the value stored for each key of a TombstoneTree. A deleted key
+// keeps its entry with Deleted set until the tree is compacted.
+type Tombstone[T any] struct {
+	Value   T
+	Deleted bool
+}
+
+// TombstoneTree is a tree whose deletes leave tombstones behind: a deleted
+// key is hidden from reads and iteration, but its entry stays in the tree
+// until Compact removes it. This lets replication protocols propagate the
+// deletions explicitly with WalkTombstones. Like Tree, it is immutable and
+// safe for concurrent reads.
+type TombstoneTree[T any] struct {
+	tree       *Tree[Tombstone[T]]
+	tombstones int
+}
+
+// NewTombstoneTree returns an empty TombstoneTree.
+func NewTombstoneTree[T any](opts ...Option[Tombstone[T]]) *TombstoneTree[T] {
+	return &TombstoneTree[T]{tree: New[Tombstone[T]](opts...)}
+}
+
+// Tree returns the underlying tree, including the tombstones.
+func (t *TombstoneTree[T]) Tree() *Tree[Tombstone[T]] {
+	return t.tree
+}
+
+// Len is used to return the number of live elements in the tree
+func (t *TombstoneTree[T]) Len() int {
+	return t.tree.Len() - t.tombstones
+}
+
+// Tombstones returns the number of deleted keys waiting to be compacted.
+func (t *TombstoneTree[T]) Tombstones() int {
+	return t.tombstones
+}
+
+// Get is used to lookup a specific live key, returning
+// the value and if it was found
+func (t *TombstoneTree[T]) Get(k []byte) (T, bool) {
+	e, ok := t.tree.Get(k)
+	if !ok || e.Deleted {
+		var zero T
+		return zero, false
+	}
+	return e.Value, true
+}
+
+// Insert is used to add or update a given key, reviving it if it was
+// deleted. The return provides the new tree, previous live value and a bool
+// indicating if any was set.
+func (t *TombstoneTree[T]) Insert(k []byte, v T) (*TombstoneTree[T], T, bool) {
+	tree, old, ok := t.tree.Insert(k, Tombstone[T]{Value: v})
+	nt := &TombstoneTree[T]{tree: tree, tombstones: t.tombstones}
+	if ok && old.Deleted {
+		nt.tombstones--
+		var zero T
+		return nt, zero, false
+	}
+	return nt, old.Value, ok
+}
+
+// Delete is used to delete a given key, leaving a tombstone. Returns the new
+// tree, old value if any, and a bool indicating if the key was live.
+func (t *TombstoneTree[T]) Delete(k []byte) (*TombstoneTree[T], T, bool) {
+	e, ok := t.tree.Get(k)
+	if !ok || e.Deleted {
+		var zero T
+		return t, zero, false
+	}
+	tree, _, _ := t.tree.Insert(k, Tombstone[T]{Deleted: true})
+	return &TombstoneTree[T]{tree: tree, tombstones: t.tombstones + 1}, e.Value, true
+}
+
+// Walk is used to walk the live keys of the tree
+func (t *TombstoneTree[T]) Walk(fn WalkFn[T]) {
+	t.WalkPrefix(nil, fn)
+}
+
+// WalkPrefix is used to walk the live keys under a prefix
+func (t *TombstoneTree[T]) WalkPrefix(prefix []byte, fn WalkFn[T]) {
+	t.tree.Root().WalkPrefix(prefix, func(k []byte, e Tombstone[T]) bool {
+		if e.Deleted {
+			return false
+		}
+		return fn(k, e.Value)
+	})
+}
+
+// WalkTombstones is used to walk the deleted keys that haven't been compacted
+// yet. Returning true from fn stops the walk.
+func (t *TombstoneTree[T]) WalkTombstones(fn func(k []byte) bool) {
+	if t.tombstones == 0 {
+		return
+	}
+	t.tree.Root().Walk(func(k []byte, e Tombstone[T]) bool {
+		if e.Deleted {
+			return fn(k)
+		}
+		return false
+	})
+}
+
+// Compact returns a tree with the tombstones physically removed, typically
+// once the deletions have been propagated to every replica.
+func (t *TombstoneTree[T]) Compact() *TombstoneTree[T] {
+	if t.tombstones == 0 {
+		return t
+	}
+	txn := t.tree.Txn(false)
+	t.WalkTombstones(func(k []byte) bool {
+		txn.Delete(k)
+		return false
+	})
+	return &TombstoneTree[T]{tree: txn.Commit()}
+}
@@ -0,0 +1,56 @@
+package iradix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTombstoneTree(t *testing.T) {
+	r := NewTombstoneTree[int]()
+	for i, k := range []string{"a", "b", "c"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	r, old, ok := r.Delete([]byte("b"))
+	if !ok || old != 1 {
+		t.Fatalf("bad: %d %v", old, ok)
+	}
+	if _, _, ok := r.Delete([]byte("b")); ok {
+		t.Fatalf("double delete should be a no-op")
+	}
+	if r.Len() != 2 || r.Tombstones() != 1 || r.Tree().Len() != 3 {
+		t.Fatalf("bad counts: %d %d", r.Len(), r.Tombstones())
+	}
+	if _, ok := r.Get([]byte("b")); ok {
+		t.Fatalf("deleted key should be hidden")
+	}
+
+	var live, dead []string
+	r.Walk(func(k []byte, _ int) bool {
+		live = append(live, string(k))
+		return false
+	})
+	r.WalkTombstones(func(k []byte) bool {
+		dead = append(dead, string(k))
+		return false
+	})
+	if !slices.Equal(live, []string{"a", "c"}) || !slices.Equal(dead, []string{"b"}) {
+		t.Fatalf("bad: %v %v", live, dead)
+	}
+
+	// Inserting revives a tombstoned key.
+	revived, old, ok := r.Insert([]byte("b"), 10)
+	if ok || old != 0 || revived.Tombstones() != 0 || revived.Len() != 3 {
+		t.Fatalf("bad revive: %d %v", old, ok)
+	}
+
+	// Compacting removes the tombstones.
+	r, _, _ = r.Delete([]byte("c"))
+	compacted := r.Compact()
+	if compacted.Tombstones() != 0 || compacted.Len() != 1 || compacted.Tree().Len() != 1 {
+		t.Fatalf("bad compaction: %d %d", compacted.Len(), compacted.Tree().Len())
+	}
+	if r.Tombstones() != 2 {
+		t.Fatalf("old tree should be unchanged")
+	}
+}
@@ -0,0 +1,57 @@
+package iradix
+
+// TrackPolicy bounds the number of watch channels a transaction tracks when
+// TrackMutate is enabled, see SetTrackPolicy.
+type TrackPolicy struct {
+	// MaxChannels is the number of tracked channels past which tracking is
+	// degraded, or zero for no limit.
+	MaxChannels int
+
+	// Granularity and Depth are the coarser notification granularity
+	// switched to once the limit is reached, see SetNotifyGranularity.
+	Granularity NotifyGranularity
+	Depth       int
+
+	// Disable stops tracking once the limit is reached instead: the
+	// channels tracked so far are still notified on commit, but nothing
+	// modified afterwards is, so the caller has to deal with the watchers
+	// of later mutations, for example by notifying them all through an
+	// ancestor it owns.
+	Disable bool
+}
+
+// SetTrackPolicy lets the transaction start with precise mutation tracking
+// and degrade it automatically if it turns out to modify so many nodes that
+// tracking them all gets too costly, rather than having to predict the size
+// of the transaction up front. Channels tracked before the limit is reached
+// are still notified on commit. TrackDegraded reports whether this happened.
+func (t *Txn[T]) SetTrackPolicy(p TrackPolicy) {
+	t.trackPolicy = p
+}
+
+// TrackDegraded returns true if the transaction's mutation tracking was
+// degraded by its track policy, see SetTrackPolicy.
+func (t *Txn[T]) TrackDegraded() bool {
+	return t.trackDegraded
+}
+
+// checkTrackPolicy degrades the mutation tracking once the number of tracked
+// channels reaches the limit of the track policy.
+func (t *Txn[T]) checkTrackPolicy() {
+	p := t.trackPolicy
+	if t.trackDegraded || p.MaxChannels <= 0 || len(t.trackChannels) < p.MaxChannels {
+		return
+	}
+	t.trackDegraded = true
+	if p.Disable {
+		return
+	}
+	t.SetNotifyGranularity(p.Granularity, p.Depth)
+}
+
+// trackStopped returns true once the track policy disabled tracking, after
+// which no new channels are tracked. Those tracked before are kept, since
+// nothing else would ever close them, and notified on commit.
+func (t *Txn[T]) trackStopped() bool {
+	return t.trackDegraded && t.trackPolicy.Disable
+}
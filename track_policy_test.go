@@ -0,0 +1,112 @@
+package iradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTrackPolicy(t *testing.T) {
+	build := func() *Tree[int] {
+		txn := New[int]().Txn(false)
+		for i := 0; i < 100; i++ {
+			txn.Insert([]byte(fmt.Sprintf("key/%03d", i)), i)
+		}
+		return txn.Commit()
+	}
+
+	t.Run("small transaction", func(t *testing.T) {
+		r := build()
+		leafWatch, _, _ := r.Root().GetWatch([]byte("key/001"))
+		txn := r.Txn(false)
+		txn.TrackMutate(true)
+		txn.SetTrackPolicy(TrackPolicy{MaxChannels: 50, Granularity: NotifyRoot})
+		txn.Insert([]byte("key/001"), -1)
+		txn.Commit()
+		if txn.TrackDegraded() || !watchFired(leafWatch) {
+			t.Fatalf("should track precisely")
+		}
+	})
+
+	t.Run("degrade", func(t *testing.T) {
+		r := build()
+		rootWatch, _, _ := r.Root().GetWatch(nil)
+		lastWatch, _, _ := r.Root().GetWatch([]byte("key/099"))
+		txn := r.Txn(false)
+		txn.TrackMutate(true)
+		txn.SetTrackPolicy(TrackPolicy{MaxChannels: 10, Granularity: NotifyRoot})
+		for i := 0; i < 100; i++ {
+			txn.Insert([]byte(fmt.Sprintf("key/%03d", i)), -i)
+		}
+		if !txn.TrackDegraded() || len(txn.trackChannels) > 12 {
+			t.Fatalf("should be degraded: %d channels", len(txn.trackChannels))
+		}
+		r = txn.Commit()
+		if hasAnyClosedMutateCh(r) {
+			t.Fatalf("bad")
+		}
+		if !watchFired(rootWatch) {
+			t.Fatalf("root should be notified")
+		}
+		if watchFired(lastWatch) {
+			t.Fatalf("leaves modified after degrading aren't notified")
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		r := build()
+		rootWatch, _, _ := r.Root().GetWatch(nil)
+		firstWatch, _, _ := r.Root().GetWatch([]byte("key/000"))
+		lastWatch, _, _ := r.Root().GetWatch([]byte("key/099"))
+		txn := r.Txn(false)
+		txn.TrackMutate(true)
+		txn.SetTrackPolicy(TrackPolicy{MaxChannels: 10, Disable: true})
+		for i := 0; i < 100; i++ {
+			txn.Insert([]byte(fmt.Sprintf("key/%03d", i)), -i)
+		}
+		if !txn.TrackDegraded() || len(txn.trackChannels) > 12 {
+			t.Fatalf("should be disabled: %d channels", len(txn.trackChannels))
+		}
+
+		// Nothing is notified before the commit, and a watcher re-reading
+		// the tree then still sees the old value.
+		if watchFired(rootWatch) || watchFired(firstWatch) {
+			t.Fatalf("nothing should be notified before commit")
+		}
+		if v, _ := r.Get([]byte("key/000")); v != 0 {
+			t.Fatalf("bad: %d", v)
+		}
+		nr := txn.Commit()
+		if hasAnyClosedMutateCh(nr) {
+			t.Fatalf("bad")
+		}
+
+		// Watchers of keys modified before the switch are notified on
+		// commit, they would block forever otherwise.
+		if !watchFired(rootWatch) || !watchFired(firstWatch) {
+			t.Fatalf("channels tracked before disabling should be notified")
+		}
+		if watchFired(lastWatch) {
+			t.Fatalf("leaves modified after disabling aren't notified")
+		}
+		if v, _ := nr.Get([]byte("key/000")); v != 0 {
+			t.Fatalf("bad: %d", v)
+		}
+		if v, _ := nr.Get([]byte("key/099")); v != -99 {
+			t.Fatalf("bad: %d", v)
+		}
+	})
+
+	t.Run("disable discarded", func(t *testing.T) {
+		r := build()
+		firstWatch, _, _ := r.Root().GetWatch([]byte("key/000"))
+		txn := r.Txn(false)
+		txn.TrackMutate(true)
+		txn.SetTrackPolicy(TrackPolicy{MaxChannels: 10, Disable: true})
+		for i := 0; i < 100; i++ {
+			txn.Insert([]byte(fmt.Sprintf("key/%03d", i)), -i)
+		}
+		if !txn.TrackDegraded() || watchFired(firstWatch) {
+			t.Fatalf("a discarded transaction shouldn't notify")
+		}
+	})
+}
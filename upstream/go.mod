@@ -0,0 +1,16 @@
+module github.com/absolutelightning/go-immutable-radix/upstream
+
+go 1.21
+
+// The root module is tagged first in a release, and this module is tagged
+// upstream/vX.Y.Z after it, requiring that version. Within the repository a
+// go.work file resolves it to the checkout instead, see CHANGELOG.md.
+require (
+	github.com/absolutelightning/go-immutable-radix v1.4.0
+	github.com/hashicorp/go-immutable-radix v1.3.1
+)
+
+require (
+	github.com/hashicorp/golang-lru v0.5.0 // indirect
+	github.com/hashicorp/golang-lru/v2 v2.0.0 // indirect
+)
@@ -0,0 +1,45 @@
+// Package upstream converts trees between this package and the non-generic
+// github.com/hashicorp/go-immutable-radix package it was forked from, to ease
+// migrating code bases from one to the other.
+package upstream
+
+import (
+	"fmt"
+
+	hciradix "github.com/hashicorp/go-immutable-radix"
+
+	iradix "github.com/absolutelightning/go-immutable-radix"
+)
+
+// FromHashicorp builds a tree holding the same keys and values as the given
+// upstream tree, configured with the given options. The keys are read in order
+// and inserted in a single transaction. An error is returned if a value is not
+// of type T.
+func FromHashicorp[T any](old *hciradix.Tree, opts ...iradix.Option[T]) (*iradix.Tree[T], error) {
+	txn := iradix.New[T](opts...).Txn(false)
+	var err error
+	old.Root().Walk(func(k []byte, v interface{}) bool {
+		val, ok := v.(T)
+		if !ok && v != nil {
+			err = fmt.Errorf("value for key %q has type %T, expected %T", k, v, val)
+			return true
+		}
+		txn.Insert(k, val)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txn.Commit(), nil
+}
+
+// ToHashicorp builds an upstream tree holding the same keys and values as the
+// given tree. The keys are read in order and inserted in a single transaction.
+func ToHashicorp[T any](t *iradix.Tree[T]) *hciradix.Tree {
+	txn := hciradix.New().Txn()
+	t.Root().Walk(func(k []byte, v T) bool {
+		txn.Insert(k, v)
+		return false
+	})
+	return txn.Commit()
+}
@@ -0,0 +1,44 @@
+package upstream
+
+import (
+	"testing"
+
+	hciradix "github.com/hashicorp/go-immutable-radix"
+)
+
+func TestRoundTrip(t *testing.T) {
+	old := hciradix.New()
+	keys := []string{"", "foo", "foo/bar", "foobar", "zip"}
+	for i, k := range keys {
+		old, _, _ = old.Insert([]byte(k), i)
+	}
+
+	r, err := FromHashicorp[int](old)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Len() != len(keys) {
+		t.Fatalf("bad len: %d", r.Len())
+	}
+	for i, k := range keys {
+		if v, ok := r.Get([]byte(k)); !ok || v != i {
+			t.Fatalf("bad value for %q: %v %v", k, v, ok)
+		}
+	}
+
+	back := ToHashicorp(r)
+	if back.Len() != len(keys) {
+		t.Fatalf("bad len: %d", back.Len())
+	}
+	for i, k := range keys {
+		if v, ok := back.Get([]byte(k)); !ok || v.(int) != i {
+			t.Fatalf("bad value for %q: %v %v", k, v, ok)
+		}
+	}
+
+	// Values of the wrong type are reported.
+	old, _, _ = old.Insert([]byte("bad"), "string")
+	if _, err := FromHashicorp[int](old); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,43 @@
+package iradix
+
+import "fmt"
+
+// KeyValidator checks a key before it is inserted into a tree, returning an
+// error if it violates the application's rules.
+type KeyValidator func(k []byte) error
+
+// WithKeyValidator makes the tree reject keys for which fn returns an error,
+// such as keys containing NUL bytes, invalid UTF-8 or overlong keys. Insert
+// leaves such keys out; TryInsert and BulkInsert return an *InvalidKeyError,
+// which is also reported by Txn.Err.
+func WithKeyValidator[T any](fn KeyValidator) Option[T] {
+	return func(c *config[T]) {
+		c.keyValidator = fn
+	}
+}
+
+// InvalidKeyError is returned for a key rejected by the tree's key validator.
+type InvalidKeyError struct {
+	Key []byte
+	Err error
+}
+
+func (e *InvalidKeyError) Error() string {
+	return fmt.Sprintf("invalid key %q: %v", e.Key, e.Err)
+}
+
+func (e *InvalidKeyError) Unwrap() error {
+	return e.Err
+}
+
+// validateKey returns an *InvalidKeyError if the key is rejected by the key
+// validator, if any.
+func (c *config[T]) validateKey(k []byte) error {
+	if c == nil || c.keyValidator == nil {
+		return nil
+	}
+	if err := c.keyValidator(k); err != nil {
+		return &InvalidKeyError{Key: k, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package iradix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var errNUL = errors.New("key contains NUL")
+
+func rejectNUL(k []byte) error {
+	if bytes.IndexByte(k, 0) >= 0 {
+		return errNUL
+	}
+	return nil
+}
+
+func TestKeyValidator(t *testing.T) {
+	r := New[int](WithKeyValidator[int](rejectNUL))
+
+	r, _, _, err := r.TryInsert([]byte("foo"), 1)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	r2, _, _, err := r.TryInsert([]byte("f\x00o"), 2)
+	var invalid *InvalidKeyError
+	if !errors.As(err, &invalid) || !errors.Is(err, errNUL) || string(invalid.Key) != "f\x00o" {
+		t.Fatalf("bad: %v", err)
+	}
+	if r2 != r {
+		t.Fatalf("tree should be unchanged")
+	}
+
+	// Insert leaves invalid keys out and the transaction records the error.
+	txn := r.Txn(false)
+	txn.Insert([]byte("bar"), 3)
+	if _, ok := txn.Insert([]byte("\x00"), 4); ok || txn.Err() == nil {
+		t.Fatalf("should be rejected")
+	}
+	txn.Insert([]byte("baz"), 5)
+	r = txn.Commit()
+	if r.Len() != 3 {
+		t.Fatalf("bad len: %d", r.Len())
+	}
+	if _, ok := r.Get([]byte("\x00")); ok {
+		t.Fatalf("invalid key stored")
+	}
+
+	// The validator is carried over to derived trees and bulk operations.
+	txn = r.Txn(false)
+	n, err := txn.BulkInsert(func(yield func([]byte, int) bool) {
+		_ = yield([]byte("a"), 1) && yield([]byte("b\x00"), 2) && yield([]byte("c"), 3)
+	})
+	if n != 1 || !errors.Is(err, errNUL) || !errors.Is(txn.Err(), errNUL) {
+		t.Fatalf("bad: %d %v", n, err)
+	}
+}
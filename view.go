@@ -0,0 +1,67 @@
+package iradix
+
+// View is a read-only view of the part of a tree under a prefix. Every key
+// passed to a View is relative to the prefix, and keys handed back by it have
+// the prefix removed. It shares the nodes of the tree it was created from, so
+// creating one is cheap.
+type View[T any] struct {
+	root   *Node[T]
+	prefix []byte
+}
+
+// View returns a view of the keys under the given prefix.
+func (n *Node[T]) View(prefix []byte) *View[T] {
+	return &View[T]{
+		root:   n,
+		prefix: concat(nil, prefix),
+	}
+}
+
+// Prefix returns the prefix the view is scoped to.
+func (v *View[T]) Prefix() []byte {
+	return v.prefix
+}
+
+// View returns a nested view, scoped to the given prefix within this one.
+func (v *View[T]) View(prefix []byte) *View[T] {
+	return v.root.View(concat(v.prefix, prefix))
+}
+
+// Get is used to lookup a specific key, returning
+// the value and if it was found
+func (v *View[T]) Get(k []byte) (T, bool) {
+	return v.root.Get(concat(v.prefix, k))
+}
+
+// GetWatch is used to lookup a specific key, returning
+// the watch channel, value and if it was found
+func (v *View[T]) GetWatch(k []byte) (<-chan struct{}, T, bool) {
+	return v.root.GetWatch(concat(v.prefix, k))
+}
+
+// LongestPrefix is like Get, but instead of an exact match, it will return the
+// longest prefix match. Only keys within the view are considered.
+func (v *View[T]) LongestPrefix(k []byte) ([]byte, T, bool) {
+	var key []byte
+	var val T
+	found := false
+	v.root.WalkPath(concat(v.prefix, k), func(fk []byte, fv T) bool {
+		if len(fk) >= len(v.prefix) {
+			key, val, found = fk[len(v.prefix):], fv, true
+		}
+		return false
+	})
+	return key, val, found
+}
+
+// Walk is used to walk every key in the view
+func (v *View[T]) Walk(fn WalkFn[T]) {
+	v.WalkPrefix(nil, fn)
+}
+
+// WalkPrefix is used to walk the keys in the view under a prefix
+func (v *View[T]) WalkPrefix(prefix []byte, fn WalkFn[T]) {
+	v.root.WalkPrefix(concat(v.prefix, prefix), func(k []byte, val T) bool {
+		return fn(k[len(v.prefix):], val)
+	})
+}
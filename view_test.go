@@ -0,0 +1,62 @@
+package iradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	r := New[int]()
+	keys := []string{"t", "t1/", "t1/a", "t1/a/b", "t1/b", "t2/a", "t10/a"}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	v := r.Root().View([]byte("t1/"))
+
+	if val, ok := v.Get([]byte("a/b")); !ok || val != 3 {
+		t.Fatalf("bad get: %v %v", val, ok)
+	}
+	if _, ok := v.Get([]byte("../t2/a")); ok {
+		t.Fatalf("unexpected get")
+	}
+	if _, val, ok := v.GetWatch(nil); !ok || val != 1 {
+		t.Fatalf("bad get watch: %v %v", val, ok)
+	}
+
+	var out []string
+	v.Walk(func(k []byte, _ int) bool {
+		out = append(out, string(k))
+		return false
+	})
+	if !reflect.DeepEqual(out, []string{"", "a", "a/b", "b"}) {
+		t.Fatalf("bad walk: %q", out)
+	}
+	out = nil
+	v.WalkPrefix([]byte("a"), func(k []byte, _ int) bool {
+		out = append(out, string(k))
+		return false
+	})
+	if !reflect.DeepEqual(out, []string{"a", "a/b"}) {
+		t.Fatalf("bad walk prefix: %q", out)
+	}
+
+	// Longest prefix matches don't escape the view.
+	if k, val, ok := v.LongestPrefix([]byte("a/c")); !ok || string(k) != "a" || val != 2 {
+		t.Fatalf("bad longest prefix: %q %v %v", k, val, ok)
+	}
+	if k, val, ok := v.LongestPrefix([]byte("c")); !ok || string(k) != "" || val != 1 {
+		t.Fatalf("bad longest prefix: %q %v %v", k, val, ok)
+	}
+	if _, _, ok := r.Root().View([]byte("t2/")).LongestPrefix([]byte("b")); ok {
+		t.Fatalf("longest prefix escaped the view")
+	}
+
+	// Nested views.
+	nested := v.View([]byte("a/"))
+	if string(nested.Prefix()) != "t1/a/" {
+		t.Fatalf("bad prefix: %q", nested.Prefix())
+	}
+	if val, ok := nested.Get([]byte("b")); !ok || val != 3 {
+		t.Fatalf("bad get: %v %v", val, ok)
+	}
+}
@@ -0,0 +1,80 @@
+package iradix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WalkErrFn is used when walking the tree with the WalkErr family of methods.
+// Returning a non-nil error stops the walk, and the error is returned by the
+// walk unless it is StopWalk.
+type WalkErrFn[T any] func(k []byte, v T) error
+
+// StopWalk can be returned by a WalkErrFn to stop the walk early without
+// reporting an error.
+var StopWalk = errors.New("stop walk")
+
+// WalkPanicError is returned by the WalkErr family of methods when the callback
+// panics. It records the key being visited and the recovered value.
+type WalkPanicError struct {
+	Key   []byte
+	Value any
+}
+
+func (e *WalkPanicError) Error() string {
+	return fmt.Sprintf("iradix: walk callback panicked at key %q: %v", e.Key, e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error.
+func (e *WalkPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// walkErr adapts fn to a WalkFn for use with walk, returning the error that
+// stopped the walk if any.
+func walkErr[T any](walk func(WalkFn[T]), fn WalkErrFn[T]) error {
+	var err error
+	walk(func(k []byte, v T) bool {
+		err = callWalkErr(fn, k, v)
+		return err != nil
+	})
+	if err == StopWalk {
+		return nil
+	}
+	return err
+}
+
+// callWalkErr calls fn, turning a panic into a WalkPanicError.
+func callWalkErr[T any](fn WalkErrFn[T], k []byte, v T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &WalkPanicError{Key: k, Value: r}
+		}
+	}()
+	return fn(k, v)
+}
+
+// WalkErr is like Walk, but the callback returns an error that stops the walk
+// and is returned, see WalkErrFn.
+func (n *Node[T]) WalkErr(fn WalkErrFn[T]) error {
+	return walkErr(n.Walk, fn)
+}
+
+// WalkBackwardsErr is like WalkBackwards, but the callback returns an error
+// that stops the walk and is returned, see WalkErrFn.
+func (n *Node[T]) WalkBackwardsErr(fn WalkErrFn[T]) error {
+	return walkErr(n.WalkBackwards, fn)
+}
+
+// WalkPrefixErr is like WalkPrefix, but the callback returns an error that
+// stops the walk and is returned, see WalkErrFn.
+func (n *Node[T]) WalkPrefixErr(prefix []byte, fn WalkErrFn[T]) error {
+	return walkErr(func(fn WalkFn[T]) { n.WalkPrefix(prefix, fn) }, fn)
+}
+
+// WalkPathErr is like WalkPath, but the callback returns an error that stops
+// the walk and is returned, see WalkErrFn.
+func (n *Node[T]) WalkPathErr(path []byte, fn WalkErrFn[T]) error {
+	return walkErr(func(fn WalkFn[T]) { n.WalkPath(path, fn) }, fn)
+}
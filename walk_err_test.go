@@ -0,0 +1,65 @@
+package iradix
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWalkErr(t *testing.T) {
+	r := New[int]()
+	keys := []string{"foo", "foo/bar", "foo/baz", "foobar", "zip"}
+	for i, k := range keys {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+	root := r.Root()
+
+	// A full walk returns nil.
+	var out []string
+	err := root.WalkErr(func(k []byte, v int) error {
+		out = append(out, string(k))
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(out, keys) {
+		t.Fatalf("bad: %v %v", out, err)
+	}
+
+	// An error stops the walk and is returned.
+	errBoom := errors.New("boom")
+	out = nil
+	err = root.WalkPrefixErr([]byte("foo/"), func(k []byte, v int) error {
+		out = append(out, string(k))
+		return errBoom
+	})
+	if err != errBoom || !reflect.DeepEqual(out, []string{"foo/bar"}) {
+		t.Fatalf("bad: %v %v", out, err)
+	}
+
+	// StopWalk stops without an error.
+	out = nil
+	err = root.WalkBackwardsErr(func(k []byte, v int) error {
+		out = append(out, string(k))
+		if len(out) == 2 {
+			return StopWalk
+		}
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(out, []string{"zip", "foo"}) {
+		t.Fatalf("bad: %v %v", out, err)
+	}
+
+	// Panics are reported with the offending key.
+	err = root.WalkPathErr([]byte("foo/baz"), func(k []byte, v int) error {
+		if string(k) == "foo/baz" {
+			panic(errBoom)
+		}
+		return nil
+	})
+	var perr *WalkPanicError
+	if !errors.As(err, &perr) || string(perr.Key) != "foo/baz" {
+		t.Fatalf("bad: %v", err)
+	}
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("panic value should be unwrapped: %v", err)
+	}
+}
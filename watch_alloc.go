@@ -0,0 +1,87 @@
+package iradix
+
+// WatchAllocation selects which nodes of a tree allocate the channels used
+// by its watches, see WithWatchAllocation.
+type WatchAllocation int
+
+const (
+	// WatchAll allocates a channel for every node and leaf that is
+	// watched, which gives the most precise notifications.
+	WatchAll WatchAllocation = iota
+
+	// WatchNodesOnly doesn't allocate channels for leaves. Watches of a
+	// key use the channel of its node instead, which also fires for
+	// changes to the keys below it.
+	WatchNodesOnly
+
+	// WatchLeavesOnly doesn't allocate channels for nodes other than the
+	// root. Watches of a key still use its leaf's channel, but watches of
+	// missing keys, prefixes and iterators use the root's channel, which
+	// fires on every change to the tree.
+	WatchLeavesOnly
+)
+
+// WithWatchAllocation lets memory constrained deployments trade the precision
+// of watches for the memory of their channels, which are otherwise allocated
+// for every watched node and leaf. The channels are dropped as the nodes are
+// rewritten by transactions, so a tree built without the option keeps its
+// channels until then. The policy only applies once a transaction is
+// committed, so watches within a transaction are unaffected.
+func WithWatchAllocation[T any](a WatchAllocation) Option[T] {
+	return func(c *config[T]) {
+		c.watchAllocation = a
+	}
+}
+
+// watchAllocationPolicy returns the watch allocation of the configuration,
+// which may be nil.
+func (c *config[T]) watchAllocationPolicy() WatchAllocation {
+	if c == nil {
+		return WatchAll
+	}
+	return c.watchAllocation
+}
+
+// updateWatchAllocation sets the watch flags of a node being settled. The
+// root, which is the only node with an empty prefix, always keeps its
+// channel for the watches falling back to it.
+func (n *Node[T]) updateWatchAllocation(a WatchAllocation) {
+	n.noWatch = a == WatchLeavesOnly && len(n.prefix) > 0
+	n.noLeafWatch = a == WatchNodesOnly
+}
+
+// hasMutateCh returns true if a watch channel was allocated for the node.
+func (n *Node[T]) hasMutateCh() bool {
+	ch := n.mutateCh.Load()
+	return ch != nil && *ch != nil
+}
+
+// watchCh returns the node's watch channel, or the given one from an ancestor
+// if the node doesn't allocate one.
+func (n *Node[T]) watchCh(fallback <-chan struct{}) <-chan struct{} {
+	if n.noWatch && !n.hasMutateCh() {
+		return fallback
+	}
+	return n.getMutateCh()
+}
+
+// leafWatchCh returns the watch channel of the node's leaf, or the node's own
+// if the leaf doesn't allocate one, see watchCh.
+func (n *Node[T]) leafWatchCh(fallback <-chan struct{}) <-chan struct{} {
+	if n.noLeafWatch && !n.leaf.hasMutateCh() {
+		return n.watchCh(fallback)
+	}
+	return n.leaf.getMutateCh()
+}
+
+// tracksLeaf returns true if the node has a leaf whose watch channel needs to
+// be notified when it is modified.
+func (n *Node[T]) tracksLeaf() bool {
+	return n.leaf != nil && (!n.noLeafWatch || n.leaf.hasMutateCh())
+}
+
+// hasMutateCh returns true if a watch channel was allocated for the leaf.
+func (n *leafNode[T]) hasMutateCh() bool {
+	ch := n.mutateCh.Load()
+	return ch != nil && *ch != nil
+}
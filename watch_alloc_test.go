@@ -0,0 +1,84 @@
+package iradix
+
+import "testing"
+
+func TestWatchAllocation(t *testing.T) {
+	build := func(a WatchAllocation) *Tree[int] {
+		txn := New[int](WithWatchAllocation[int](a)).Txn(false)
+		for i, k := range []string{"foo", "foobar", "foobaz", "zip"} {
+			txn.Insert([]byte(k), i)
+		}
+		return txn.Commit()
+	}
+	update := func(r *Tree[int], k string) *Tree[int] {
+		txn := r.Txn(false)
+		txn.TrackMutate(true)
+		txn.Insert([]byte(k), 100)
+		return txn.Commit()
+	}
+	allocated := func(r *Tree[int]) (nodes, leaves int) {
+		for it := r.root.rawIterator(); it.Front() != nil; it.Next() {
+			n := it.Front()
+			if n.hasMutateCh() {
+				nodes++
+			}
+			if n.leaf != nil && n.leaf.hasMutateCh() {
+				leaves++
+			}
+		}
+		return nodes, leaves
+	}
+
+	t.Run("nodes only", func(t *testing.T) {
+		r := build(WatchNodesOnly)
+		watch, _, ok := r.Root().GetWatch([]byte("foobar"))
+		if !ok {
+			t.Fatalf("missing key")
+		}
+		r.Root().GetWatch([]byte("zip"))
+		if _, leaves := allocated(r); leaves != 0 {
+			t.Fatalf("leaves should not allocate channels: %d", leaves)
+		}
+		update(r, "foobar")
+		if !watchFired(watch) {
+			t.Fatalf("should fire")
+		}
+	})
+
+	t.Run("leaves only", func(t *testing.T) {
+		r := build(WatchLeavesOnly)
+		leafWatch, _, _ := r.Root().GetWatch([]byte("foobar"))
+		missWatch, _, _ := r.Root().GetWatch([]byte("foobarx"))
+		prefixWatch := r.Root().WalkPrefixWatch([]byte("foob"), func([]byte, int) bool { return false })
+		lpWatch, _, _, _ := r.Root().LongestPrefixWatch([]byte("foobazz"))
+		if missWatch != r.Root().getMutateCh() || prefixWatch != missWatch || lpWatch != missWatch {
+			t.Fatalf("node watches should fall back to the root")
+		}
+		if nodes, leaves := allocated(r); nodes != 1 || leaves != 1 {
+			t.Fatalf("bad allocation: %d nodes, %d leaves", nodes, leaves)
+		}
+
+		r = update(r, "zip")
+		if watchFired(leafWatch) || !watchFired(missWatch) {
+			t.Fatalf("only the root should fire")
+		}
+		leafWatch, _, _ = r.Root().GetWatch([]byte("foobar"))
+		update(r, "foobar")
+		if !watchFired(leafWatch) {
+			t.Fatalf("leaf should fire")
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		r := build(WatchAll)
+		leafWatch, _, _ := r.Root().GetWatch([]byte("foobar"))
+		missWatch, _, _ := r.Root().GetWatch([]byte("foobarx"))
+		if missWatch == r.Root().getMutateCh() || leafWatch == missWatch {
+			t.Fatalf("watches should be precise")
+		}
+		r = update(r, "zip")
+		if watchFired(leafWatch) || watchFired(missWatch) {
+			t.Fatalf("should not fire")
+		}
+	})
+}
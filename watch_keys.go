@@ -0,0 +1,51 @@
+package iradix
+
+import (
+	"reflect"
+	"sync"
+)
+
+// WatchKeys returns a single channel closed on the first change to any of the
+// keys, so a caller watching many keys doesn't have to select over their
+// channels itself. Like GetWatch, a missing key is watched through its
+// closest node, which also fires on changes to nearby keys. The channels are
+// fanned in by one goroutine, which exits once a change is seen or cancel is
+// called; cancel must be called when the channel is no longer needed if it
+// may never fire. Calling cancel more than once is fine, and it returns once
+// the goroutine has exited. The returned channel isn't closed by cancel.
+func (n *Node[T]) WatchKeys(keys [][]byte) (<-chan struct{}, func()) {
+	done := make(chan struct{})
+	stop := make(chan struct{})
+	exited := make(chan struct{})
+
+	// The first case is the cancellation, the others the distinct watch
+	// channels of the keys.
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)}}
+	seen := make(map[<-chan struct{}]struct{}, len(keys))
+	for _, k := range keys {
+		watch, _, _ := n.GetWatch(k)
+		if _, ok := seen[watch]; ok {
+			continue
+		}
+		seen[watch] = struct{}{}
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(watch)})
+	}
+
+	go func() {
+		defer close(exited)
+		if len(cases) == 1 {
+			<-stop
+			return
+		}
+		if chosen, _, _ := reflect.Select(cases); chosen != 0 {
+			close(done)
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() { close(stop) })
+		<-exited
+	}
+	return done, cancel
+}
@@ -0,0 +1,77 @@
+package iradix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchKeys(t *testing.T) {
+	r := New[int]()
+	for i, k := range []string{"a", "b", "c", "d"} {
+		r, _, _ = r.Insert([]byte(k), i)
+	}
+
+	keys := [][]byte{[]byte("a"), []byte("c"), []byte("a")}
+	done, cancel := r.Root().WatchKeys(keys)
+	defer cancel()
+	if watchFired(done) {
+		t.Fatalf("should not fire")
+	}
+
+	// A change to an unwatched key doesn't fire it.
+	txn := r.Txn(false)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("b"), 10)
+	r = txn.Commit()
+	time.Sleep(10 * time.Millisecond)
+	if watchFired(done) {
+		t.Fatalf("should not fire")
+	}
+
+	txn = r.Txn(false)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("c"), 20)
+	r = txn.Commit()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("should fire")
+	}
+	cancel()
+
+	// Cancelling stops the goroutine without firing the channel.
+	done, cancel = r.Root().WatchKeys(keys)
+	cancel()
+	cancel()
+	if watchFired(done) {
+		t.Fatalf("cancel should not fire")
+	}
+	txn = r.Txn(false)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("a"), 30)
+	r = txn.Commit()
+	time.Sleep(10 * time.Millisecond)
+	if watchFired(done) {
+		t.Fatalf("should be cancelled")
+	}
+
+	// A missing key fires when it is inserted.
+	done, cancel = r.Root().WatchKeys([][]byte{[]byte("e")})
+	defer cancel()
+	txn = r.Txn(false)
+	txn.TrackMutate(true)
+	txn.Insert([]byte("e"), 40)
+	r = txn.Commit()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("should fire")
+	}
+
+	// No keys never fires but can still be cancelled.
+	done, cancel = r.Root().WatchKeys(nil)
+	cancel()
+	if watchFired(done) {
+		t.Fatalf("should not fire")
+	}
+}
@@ -0,0 +1,65 @@
+package iradix
+
+import "bytes"
+
+// watermarks are the smallest and largest keys ever written to a tree.
+type watermarks struct {
+	low, high []byte
+	written   bool
+}
+
+// update records a key written, inserted or deleted.
+func (w *watermarks) update(k []byte) {
+	if !w.written {
+		w.low, w.high, w.written = k, k, true
+		return
+	}
+	if bytes.Compare(k, w.low) < 0 {
+		w.low = k
+	}
+	if bytes.Compare(k, w.high) > 0 {
+		w.high = k
+	}
+}
+
+// Watermarks returns the smallest and largest keys ever written to the tree
+// and its ancestors, inserted or deleted, even if they are no longer in the
+// tree. The bool is false if nothing was ever written. They bound the
+// keyspace a retention job has to consider.
+func (t *Tree[T]) Watermarks() ([]byte, []byte, bool) {
+	return t.marks.low, t.marks.high, t.marks.written
+}
+
+// PrefixVersion returns the last-modified version of the keys under the given
+// prefix, which can be compared with the versions returned later to find out
+// if the prefix was modified since. It is the generation of the subtree
+// holding the keys, see Node.Generation, which only changes when a commit
+// modifies the subtree, and only ever increases. If there are no keys under
+// the prefix it is the generation of the node they would be added to, which
+// may also change with the keys next to them.
+func (t *Tree[T]) PrefixVersion(prefix []byte) uint64 {
+	n := t.root
+	search := prefix
+	for len(search) > 0 {
+		_, child := n.getEdge(search[0])
+		if child == nil {
+			break
+		}
+		if bytes.HasPrefix(search, child.prefix) {
+			search = search[len(child.prefix):]
+		} else if bytes.HasPrefix(child.prefix, search) {
+			return child.generation
+		} else {
+			break
+		}
+		n = child
+	}
+	return n.generation
+}
+
+// ModifiedSince returns true if the keys under the given prefix may have been
+// modified since PrefixVersion returned the given version, so that retention
+// jobs can skip the prefixes untouched since their last run.
+func (t *Tree[T]) ModifiedSince(prefix []byte, version uint64) bool {
+	return t.PrefixVersion(prefix) > version
+}
@@ -0,0 +1,75 @@
+package iradix
+
+import "testing"
+
+func TestWatermarks(t *testing.T) {
+	r := New[int]()
+	if _, _, ok := r.Watermarks(); ok {
+		t.Fatalf("nothing written yet")
+	}
+
+	txn := r.Txn(false)
+	txn.Insert([]byte("m"), 1)
+	txn.Insert([]byte("c"), 2)
+	txn.Insert([]byte("x/1"), 3)
+	txn.Insert([]byte("x/2"), 4)
+	r = txn.Commit()
+	check := func(r *Tree[int], low, high string) {
+		t.Helper()
+		l, h, ok := r.Watermarks()
+		if !ok || string(l) != low || string(h) != high {
+			t.Fatalf("got %q %q %v want %q %q", l, h, ok, low, high)
+		}
+	}
+	check(r, "c", "x/2")
+
+	// Deleted keys were still written.
+	r2, _, _ := r.Delete([]byte("c"))
+	r2, _ = r2.DeletePrefix([]byte("x/"))
+	check(r2, "c", "x/2")
+	r3, _, _ := r2.Insert([]byte("a"), 5)
+	check(r3, "a", "x/2")
+	check(r, "c", "x/2")
+
+	// Missing keys aren't written.
+	r4, _, _ := r2.Delete([]byte("0"))
+	r4, _ = r4.DeletePrefix([]byte("zz"))
+	check(r4, "c", "x/2")
+}
+
+func TestPrefixVersion(t *testing.T) {
+	txn := New[int]().Txn(false)
+	for i, k := range []string{"logs/a", "logs/b", "metrics/a", "metrics/b"} {
+		txn.Insert([]byte(k), i)
+	}
+	r := txn.Commit()
+	logs := r.PrefixVersion([]byte("logs/"))
+	metrics := r.PrefixVersion([]byte("metrics/"))
+	missing := r.PrefixVersion([]byte("traces/"))
+
+	r2, _, _ := r.Insert([]byte("metrics/c"), 5)
+	if r2.ModifiedSince([]byte("logs/"), logs) {
+		t.Fatalf("logs weren't modified")
+	}
+	if r2.ModifiedSince([]byte("lo"), logs) {
+		t.Fatalf("logs weren't modified")
+	}
+	if !r2.ModifiedSince([]byte("metrics/"), metrics) {
+		t.Fatalf("metrics were modified")
+	}
+	if !r2.ModifiedSince([]byte("metrics/c"), missing) {
+		t.Fatalf("metrics/c was added")
+	}
+
+	r3, _ := r2.DeletePrefix([]byte("logs/"))
+	if !r3.ModifiedSince([]byte("logs/"), logs) {
+		t.Fatalf("logs were deleted")
+	}
+	r4, _, _ := r3.Insert([]byte("traces/a"), 6)
+	if !r4.ModifiedSince([]byte("traces/"), missing) {
+		t.Fatalf("traces were added")
+	}
+	if r.PrefixVersion([]byte("logs/")) != logs {
+		t.Fatalf("old tree changed")
+	}
+}
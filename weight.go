@@ -0,0 +1,109 @@
+package iradix
+
+import (
+	"math/rand"
+)
+
+// LeafWeightFn is used to compute the weight of a single leaf.
+type LeafWeightFn[T any] func(k []byte, v T) uint64
+
+// WithLeafWeight enables weighted selection of leaves with PickWeighted. Every
+// node keeps the sum of the weights of the leaves below it, which is updated
+// for the modified nodes when a transaction is committed.
+func WithLeafWeight[T any](fn LeafWeightFn[T]) Option[T] {
+	return func(c *config[T]) {
+		c.leafWeight = fn
+	}
+}
+
+// updateWeight computes the weight of the node from its leaf and the weights of
+// its children, which must already be up to date.
+func (n *Node[T]) updateWeight(fn LeafWeightFn[T]) {
+	var w uint64
+	if n.leaf != nil {
+		w = fn(n.leaf.key, n.leaf.val)
+	}
+	for _, e := range n.edges {
+		w += e.node.Weight()
+	}
+	n.agg.weight = w
+}
+
+// Weight returns the sum of the leaf weights under this node. It is zero if
+// the tree was not created with WithLeafWeight.
+func (n *Node[T]) Weight() uint64 {
+	if n.agg == nil {
+		return 0
+	}
+	return n.agg.weight
+}
+
+// PickWeighted selects a random leaf under the given prefix, with a probability
+// proportional to its weight. It descends the tree once using the weights kept
+// in every node, so it doesn't visit the other leaves. Returns false if there
+// is nothing with a positive weight under the prefix, or the tree was not
+// created with WithLeafWeight. If rng is nil the global source is used.
+func (n *Node[T]) PickWeighted(prefix []byte, rng *rand.Rand) ([]byte, T, bool) {
+	var zero T
+	n = n.prefixNode(prefix)
+	if n == nil {
+		return nil, zero, false
+	}
+	if n.Weight() == 0 {
+		return nil, zero, false
+	}
+
+	var r uint64
+	if rng != nil {
+		r = randUint64n(rng.Int63, n.Weight())
+	} else {
+		r = randUint64n(rand.Int63, n.Weight())
+	}
+	for {
+		// The leaf's own weight is whatever isn't accounted for by the
+		// children.
+		leafWeight := n.Weight()
+		for _, e := range n.edges {
+			leafWeight -= e.node.Weight()
+		}
+		if n.leaf != nil && r < leafWeight {
+			return n.leaf.key, n.leaf.val, true
+		}
+		r -= leafWeight
+
+		var next *Node[T]
+		for _, e := range n.edges {
+			if r < e.node.Weight() {
+				next = e.node
+				break
+			}
+			r -= e.node.Weight()
+		}
+		if next == nil {
+			// Can only happen if the weights are stale.
+			return nil, zero, false
+		}
+		n = next
+	}
+}
+
+// randUint64n returns a uniform random number in [0, n) using the given source
+// of non-negative 63 bit numbers.
+func randUint64n(int63 func() int64, n uint64) uint64 {
+	if n <= 1<<63 {
+		// Reject the top of the range to avoid modulo bias.
+		max := (1 << 63) - (1<<63)%n
+		for {
+			v := uint64(int63())
+			if v < max {
+				return v % n
+			}
+		}
+	}
+	for {
+		v := uint64(int63())<<1 | uint64(int63()&1)
+		if v < n {
+			return v
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package iradix
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestPickWeighted(t *testing.T) {
+	weight := func(k []byte, v int) uint64 { return uint64(v) }
+	r := New[int](WithLeafWeight(weight))
+	txn := r.Txn(false)
+	txn.Insert([]byte("svc/a/1"), 1)
+	txn.Insert([]byte("svc/a/2"), 3)
+	txn.Insert([]byte("svc/b"), 6)
+	txn.Insert([]byte("svc/b/1"), 0)
+	txn.Insert([]byte("other"), 100)
+	r = txn.Commit()
+
+	if w := r.Root().Weight(); w != 110 {
+		t.Fatalf("bad weight: %d", w)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	const picks = 10000
+	for i := 0; i < picks; i++ {
+		k, v, ok := r.Root().PickWeighted([]byte("svc/"), rng)
+		if !ok {
+			t.Fatalf("no pick")
+		}
+		if !strings.HasPrefix(string(k), "svc/") {
+			t.Fatalf("picked outside prefix: %q", k)
+		}
+		if got, _ := r.Get(k); got != v {
+			t.Fatalf("bad value for %q", k)
+		}
+		counts[string(k)]++
+	}
+	if counts["svc/b/1"] != 0 {
+		t.Fatalf("picked a zero weight leaf")
+	}
+	for k, w := range map[string]int{"svc/a/1": 1, "svc/a/2": 3, "svc/b": 6} {
+		expect := picks * w / 10
+		if c := counts[k]; c < expect*8/10 || c > expect*12/10 {
+			t.Fatalf("bad distribution for %q: %d, expected ~%d", k, c, expect)
+		}
+	}
+
+	// A prefix that splits a node's prefix still works.
+	if k, _, ok := r.Root().PickWeighted([]byte("oth"), rng); !ok || string(k) != "other" {
+		t.Fatalf("bad pick: %q", k)
+	}
+	for _, p := range []string{"svc/b/", "svc/c", "x"} {
+		if _, _, ok := r.Root().PickWeighted([]byte(p), rng); ok {
+			t.Fatalf("unexpected pick under %q", p)
+		}
+	}
+
+	// Weights follow updates and deletes.
+	txn = r.Txn(false)
+	txn.Delete([]byte("other"))
+	txn.DeletePrefix([]byte("svc/a"))
+	txn.Insert([]byte("svc/b/1"), 2)
+	r2 := txn.Commit()
+	if w := r2.Root().Weight(); w != 8 {
+		t.Fatalf("bad weight: %d", w)
+	}
+	if w := r.Root().Weight(); w != 110 {
+		t.Fatalf("old tree weight changed: %d", w)
+	}
+
+	// Trees without weights never pick anything.
+	plain, _, _ := New[int]().Insert([]byte("foo"), 1)
+	if _, _, ok := plain.Root().PickWeighted(nil, nil); ok {
+		t.Fatalf("unexpected pick")
+	}
+}